@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultUnaligned is the alignment of the characters devoted to no god,
+// unless the universe names it otherwise.
+const DefaultUnaligned = "Unaligned"
+
+// Relations between the alignment of an upgrade and the character's.
+const (
+	RelationAligned = "aligned"
+	RelationAllied  = "allied"
+	RelationOpposed = "opposed"
+)
+
+// Alignment holds the rules deriving the character's alignment from the
+// gauges tracking its devotion to each god, and pricing the upgrades by
+// alignment instead of aptitudes.
+type Alignment struct {
+	// Gods are the names of the gods, each one tracked by the gauge of the
+	// same name.
+	Gods []string `yaml:"gods"`
+
+	// Unaligned is the name of the alignment of the characters devoted to no
+	// god.
+	Unaligned string `yaml:"unaligned"`
+
+	// Threshold is the minimum value of a god's gauge for the character to
+	// be aligned to it.
+	Threshold int `yaml:"threshold"`
+
+	// Margin is the value a god's gauge must exceed each other god's gauge
+	// by for the character to be aligned to it.
+	Margin int `yaml:"margin"`
+
+	// Opposed gives the god opposed to each god.
+	Opposed map[string]string `yaml:"opposed"`
+
+	// Matches gives the number of matches, as used by the cost model, of
+	// each relation between the alignments of the upgrade and character.
+	Matches map[string]int `yaml:"matches"`
+
+	// Track increments the gauge of a god each time an upgrade aligned to it
+	// is applied, and decrements it when reverted.
+	Track bool `yaml:"track"`
+}
+
+// Check returns an error if the rules are inconsistent.
+func (a Alignment) Check() error {
+	if len(a.Gods) == 0 {
+		return fmt.Errorf("no god defined")
+	}
+
+	for god, opposed := range a.Opposed {
+		if !a.IsGod(god) {
+			return fmt.Errorf("undefined god %s", god)
+		}
+		if !a.IsGod(opposed) {
+			return fmt.Errorf("undefined god %s", opposed)
+		}
+	}
+
+	for relation := range a.Matches {
+		switch relation {
+		case RelationAligned, RelationAllied, RelationOpposed:
+		default:
+			return fmt.Errorf("undefined relation %s", relation)
+		}
+	}
+
+	return nil
+}
+
+// IsGod returns true if the name is one of the gods.
+func (a Alignment) IsGod(name string) bool {
+	for _, god := range a.Gods {
+		if strings.EqualFold(god, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnalignedName returns the name of the alignment of the characters devoted
+// to no god.
+func (a Alignment) UnalignedName() string {
+	if len(a.Unaligned) == 0 {
+		return DefaultUnaligned
+	}
+	return a.Unaligned
+}
+
+// Of returns the alignment of the character: the god whose gauge is the
+// highest, if it reaches the threshold and exceeds every other god's gauge
+// by more than the margin, or unaligned otherwise.
+func (a Alignment) Of(character Character) string {
+	best, first, second := "", 0, 0
+	for _, god := range a.Gods {
+		value := 0
+		for _, gauge := range character.Gauges {
+			if strings.EqualFold(gauge.Name, god) {
+				value = gauge.Value
+			}
+		}
+		switch {
+		case len(best) == 0 || value > first:
+			best, first, second = god, value, first
+		case value > second:
+			second = value
+		}
+	}
+
+	if first <= 0 || first < a.Threshold || first-second <= a.Margin {
+		return a.UnalignedName()
+	}
+	return best
+}
+
+// Relation returns the relation between an upgrade aligned to the given
+// gods and a character of the given alignment. The upgrades aligned to no god
+// are aligned to the unaligned characters, and allied to the others.
+func (a Alignment) Relation(alignment string, gods []string) string {
+	unaligned := true
+	for _, god := range gods {
+		if a.IsGod(god) {
+			unaligned = false
+		}
+	}
+
+	if strings.EqualFold(alignment, a.UnalignedName()) {
+		if unaligned {
+			return RelationAligned
+		}
+		return RelationAllied
+	}
+
+	for _, god := range gods {
+		if strings.EqualFold(god, alignment) {
+			return RelationAligned
+		}
+	}
+
+	for _, god := range gods {
+		if strings.EqualFold(a.Opposed[alignment], god) || strings.EqualFold(a.Opposed[god], alignment) {
+			return RelationOpposed
+		}
+	}
+
+	return RelationAllied
+}
+
+// Devote updates the gauges of the gods the upgrade is aligned to.
+func (a Alignment) Devote(character *Character, universe Universe, gods []string, upgrade Upgrade) {
+	if !a.Track {
+		return
+	}
+
+	var delta int
+	switch upgrade.Mark {
+	case MarkApply:
+		delta = 1
+	case MarkRevert:
+		delta = -1
+	default:
+		return
+	}
+
+	for _, god := range gods {
+		if !a.IsGod(god) {
+			continue
+		}
+
+		gauge := Gauge{Name: god}
+		for _, g := range universe.Gauges {
+			if strings.EqualFold(g.Name, god) {
+				gauge = g
+			}
+		}
+		if current, found := character.Gauges[gauge.Name]; found {
+			gauge = current
+		}
+
+		gauge.Value += delta
+		character.Gauges[gauge.Name] = gauge
+	}
+}
+
+// Matches returns the number of matches of an upgrade with the given
+// aptitudes and alignments for the character: the number of aptitudes the
+// character has, or, if the universe defines alignments, the number of
+// matches of the relation between the alignments of the upgrade and the
+// character.
+func (u Universe) Matches(character Character, aptitudes []Aptitude, gods []string) int {
+	if u.Alignment == nil {
+		return character.Intersect(aptitudes)
+	}
+
+	return u.Alignment.Matches[u.Alignment.Relation(u.Alignment.Of(character), gods)]
+}
+
+// alignments returns the gods the upgrade priced by the coster is aligned
+// to.
+func alignments(coster Coster) []string {
+	switch coster := coster.(type) {
+	case Characteristic:
+		return coster.Alignments
+	case Skill:
+		return coster.Alignments
+	case Talent:
+		return coster.Alignments
+	}
+	return nil
+}
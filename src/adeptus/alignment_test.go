@@ -0,0 +1,137 @@
+package main
+
+import (
+	"testing"
+)
+
+func newAlignment() Alignment {
+	return Alignment{
+		Gods:      []string{"Khorne", "Nurgle", "Slaanesh", "Tzeentch"},
+		Threshold: 2,
+		Margin:    1,
+		Opposed: map[string]string{
+			"Khorne": "Slaanesh",
+			"Nurgle": "Tzeentch",
+		},
+		Matches: map[string]int{
+			RelationAligned: 2,
+			RelationAllied:  1,
+			RelationOpposed: 0,
+		},
+		Track: true,
+	}
+}
+
+func Test_Alignment_Of(t *testing.T) {
+	alignment := newAlignment()
+
+	cases := []struct {
+		gauges map[string]int
+		out    string
+	}{
+		{gauges: map[string]int{}, out: "Unaligned"},
+		{gauges: map[string]int{"khorne": 1}, out: "Unaligned"},
+		{gauges: map[string]int{"Khorne": 3, "Nurgle": 2}, out: "Unaligned"},
+		{gauges: map[string]int{"Khorne": 4, "Nurgle": 2}, out: "Khorne"},
+		{gauges: map[string]int{"Khorne": 2, "Tzeentch": 5, "Nurgle": 2}, out: "Tzeentch"},
+	}
+
+	for i, c := range cases {
+		character := Character{Gauges: map[string]Gauge{}}
+		for name, value := range c.gauges {
+			character.Gauges[name] = Gauge{Name: name, Value: value}
+		}
+
+		out := alignment.Of(character)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %s", c.out)
+			t.Logf("	Having %s", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Alignment_Relation(t *testing.T) {
+	alignment := newAlignment()
+
+	cases := []struct {
+		alignment string
+		gods      []string
+		out       string
+	}{
+		{alignment: "Unaligned", gods: nil, out: RelationAligned},
+		{alignment: "Unaligned", gods: []string{"Khorne"}, out: RelationAllied},
+		{alignment: "Khorne", gods: []string{"khorne", "Nurgle"}, out: RelationAligned},
+		{alignment: "Khorne", gods: []string{"Nurgle"}, out: RelationAllied},
+		{alignment: "Khorne", gods: nil, out: RelationAllied},
+		{alignment: "Khorne", gods: []string{"Slaanesh"}, out: RelationOpposed},
+		{alignment: "Slaanesh", gods: []string{"Khorne"}, out: RelationOpposed},
+	}
+
+	for i, c := range cases {
+		out := alignment.Relation(c.alignment, c.gods)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %s", c.out)
+			t.Logf("	Having %s", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_NewCharacter_Alignment(t *testing.T) {
+	alignment := newAlignment()
+	universe := Universe{
+		Gauges: []Gauge{
+			{Name: "Khorne"},
+		},
+		Skills: []Skill{
+			{Name: "Athletics", Alignments: []string{"Khorne"}},
+			{Name: "Charm", Alignments: []string{"Slaanesh"}},
+		},
+		Talents: []Talent{
+			{Name: "Frenzy", Tier: 1, Alignments: []string{"Khorne"}},
+		},
+		Costs: CostMatrix{
+			"skill":  {0: {1: 300, 2: 600}, 1: {1: 200, 2: 400}, 2: {1: 100, 2: 200}},
+			"talent": {0: {1: 600}, 1: {1: 400}, 2: {1: 200}},
+		},
+		Alignment: &alignment,
+	}
+
+	sheet := Sheet{
+		Sessions: []Session{
+			{
+				Reward: IntP(2000),
+				Upgrades: []Upgrade{
+					{Mark: MarkApply, Name: "Athletics", Line: 2},
+					{Mark: MarkApply, Name: "Athletics", Line: 3},
+					{Mark: MarkApply, Name: "Frenzy", Line: 4},
+					{Mark: MarkApply, Name: "Charm", Line: 5},
+				},
+				Line: 1,
+			},
+		},
+	}
+
+	c, err := NewCharacter(universe, sheet, Options{})
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	// Unaligned: allied, allied; then aligned to Khorne: aligned, opposed.
+	expected := []int{200, 400, 200, 300}
+	for i, record := range c.Balances[0].Upgrades {
+		if *record.Cost != expected[i] {
+			t.Logf("Unexpected cost of %s: expected %d, having %d", record.Name, expected[i], *record.Cost)
+			t.Fail()
+		}
+	}
+
+	if c.Alignment != "Khorne" || c.Gauges["Khorne"].Value != 3 {
+		t.Logf("Unexpected alignment %s with %v", c.Alignment, c.Gauges)
+		t.Fail()
+	}
+}
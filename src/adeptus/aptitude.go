@@ -1,5 +1,10 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Aptitude represents an aptitude, required to purchase upgrades.
 type Aptitude string
 
@@ -15,7 +20,7 @@ func (a Aptitude) Apply(character *Character, upgrade Upgrade) error {
 
 	switch upgrade.Mark {
 	case MarkSpecial:
-		return NewError(ForbidenUpgradeMark, upgrade.Line)
+		return NewError(ForbidenUpgradeMark, upgrade.Position())
 
 	case MarkApply:
 		character.Aptitudes[string(a)] = a
@@ -23,7 +28,7 @@ func (a Aptitude) Apply(character *Character, upgrade Upgrade) error {
 	case MarkRevert:
 		_, found := character.Aptitudes[string(a)]
 		if !found {
-			return NewError(ForbidenUpgradeLoss, upgrade.Line)
+			return NewError(ForbidenUpgradeLoss, upgrade.Position())
 		}
 		delete(character.Aptitudes, string(a))
 	}
@@ -34,3 +39,49 @@ func (a Aptitude) Apply(character *Character, upgrade Upgrade) error {
 func (a Aptitude) DefaultName() string {
 	return string(a)
 }
+
+// Policies applied when a background gives an aptitude the character already
+// has.
+const (
+	// PolicyIgnore ignores the duplicate aptitude with a warning.
+	PolicyIgnore = "ignore"
+
+	// PolicySubstitute replaces the duplicate aptitude by the next option of
+	// the background in the header.
+	PolicySubstitute = "substitute"
+
+	// PolicyError stops the compilation.
+	PolicyError = "error"
+)
+
+// AptitudePolicy is the policy of the universe regarding the duplicate
+// aptitudes given by the backgrounds.
+type AptitudePolicy struct {
+	Policy string `yaml:"policy"`
+
+	// Substitutes are the aptitudes allowed as replacement. Any aptitude is
+	// allowed if empty.
+	Substitutes []Aptitude `yaml:"substitutes"`
+}
+
+// Check returns an error if the policy is undefined.
+func (p AptitudePolicy) Check() error {
+	switch p.Policy {
+	case "", PolicyIgnore, PolicySubstitute, PolicyError:
+		return nil
+	}
+	return fmt.Errorf("undefined duplicate aptitudes policy %s", p.Policy)
+}
+
+// Allows returns true if the aptitude can replace a duplicate aptitude.
+func (p AptitudePolicy) Allows(aptitude Aptitude) bool {
+	if len(p.Substitutes) == 0 {
+		return true
+	}
+	for _, substitute := range p.Substitutes {
+		if strings.EqualFold(string(substitute), string(aptitude)) {
+			return true
+		}
+	}
+	return false
+}
@@ -97,6 +97,27 @@ func Test_Aptitude_Apply(t *testing.T) {
 			},
 			err: false,
 		},
+		// The duplicate aptitudes of the sessions and elite advances are
+		// kept once.
+		{
+			upgrade: Upgrade{
+				Mark: MarkApply,
+				Line: 0,
+				Cost: nil,
+				Name: "awesomeness",
+			},
+			character: Character{
+				Aptitudes: map[string]Aptitude{
+					"awesomeness": Aptitude("awesomeness"),
+				},
+			},
+			out: Character{
+				Aptitudes: map[string]Aptitude{
+					"awesomeness": Aptitude("awesomeness"),
+				},
+			},
+			err: false,
+		},
 	} {
 		err := a.Apply(&c.character, c.upgrade)
 		if (err != nil) != c.err {
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Discrepancy is an upgrade of a session whose explicit cost differs from the
+// cost computed from the universe when it was applied.
+type Discrepancy struct {
+	Session Session
+	Record  Record
+}
+
+// Delta returns the experience paid in excess, negative if the upgrade was
+// underpaid.
+func (d Discrepancy) Delta() int {
+	return *d.Record.Cost - *d.Record.Computed
+}
+
+// Kind returns the kind of the discrepancy: overpaid, underpaid, or free for
+// the upgrades that should have cost nothing.
+func (d Discrepancy) Kind() string {
+	switch {
+	case d.Record.Mark == MarkApply && *d.Record.Computed == 0:
+		return "free"
+	case d.Delta() > 0:
+		return "overpaid"
+	default:
+		return "underpaid"
+	}
+}
+
+// Audit returns the upgrades of the sessions whose explicit cost differs from
+// the cost computed from the universe. Special rules have no computed cost and
+// the upgrades granted by elite advances are free, so they are never
+// reported.
+func (c *Character) Audit() []Discrepancy {
+	discrepancies := []Discrepancy{}
+	for _, balance := range c.Balances {
+		for _, record := range balance.Upgrades {
+			if !record.Explicit || record.Computed == nil || record.Type == "rule" || len(record.Elite) != 0 {
+				continue
+			}
+
+			if *record.Cost == *record.Computed {
+				continue
+			}
+
+			discrepancies = append(discrepancies, Discrepancy{
+				Session: balance.Session,
+				Record:  record,
+			})
+		}
+	}
+	return discrepancies
+}
+
+// PrintAudit displays the upgrades whose explicit cost differs from the cost
+// computed from the universe, along with the total delta.
+func (c *Character) PrintAudit() {
+	// Print the name.
+	fmt.Printf("%s\t%s\n", theme.Title("Name"), c.Name)
+
+	discrepancies := c.Audit()
+
+	fmt.Printf("\n%s (%s)\n", theme.Title("Discrepancies"), theme.Value(len(discrepancies)))
+
+	total := 0
+	w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+	for _, d := range discrepancies {
+		total += d.Delta()
+		fmt.Fprintf(w, "%s\t%s %s\t%s\t%d\t%d\t%+d\t%s\n",
+			d.Session.Date.Format("2006/01/02"),
+			d.Record.Mark,
+			strings.Title(d.Record.Name),
+			d.Record.Type,
+			*d.Record.Cost,
+			*d.Record.Computed,
+			d.Delta(),
+			d.Kind(),
+		)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%s\t%+d\n", theme.Title("Total delta"), total)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Character_Audit(t *testing.T) {
+	overpaid := Record{Upgrade: Upgrade{Mark: MarkApply, Name: "Awareness", Cost: IntP(250)}, Type: "skill", Explicit: true, Computed: IntP(200)}
+	underpaid := Record{Upgrade: Upgrade{Mark: MarkApply, Name: "Dodge", Cost: IntP(100)}, Type: "skill", Explicit: true, Computed: IntP(200)}
+	free := Record{Upgrade: Upgrade{Mark: MarkApply, Name: "Agility", Cost: IntP(100)}, Type: "aptitude", Explicit: true, Computed: IntP(0)}
+
+	c := Character{
+		Balances: []Balance{
+			{
+				Upgrades: []Record{
+					overpaid,
+					{Upgrade: Upgrade{Mark: MarkApply, Name: "Catfall", Cost: IntP(300)}, Type: "talent", Computed: IntP(300)},
+					{Upgrade: Upgrade{Mark: MarkApply, Name: "Rule: Debt", Cost: IntP(100)}, Type: "rule", Explicit: true, Computed: IntP(0)},
+				},
+			},
+			{
+				Upgrades: []Record{
+					underpaid,
+					free,
+					{Upgrade: Upgrade{Mark: MarkApply, Name: "Iron Jaw", Cost: IntP(300)}, Type: "talent", Explicit: true, Computed: IntP(300)},
+				},
+			},
+		},
+	}
+
+	out := c.Audit()
+	expected := []Discrepancy{
+		{Record: overpaid},
+		{Record: underpaid},
+		{Record: free},
+	}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Logf("Unexpected output:")
+		t.Logf("	Expected %v", expected)
+		t.Logf("	Having %v", out)
+		t.Fail()
+	}
+
+	kinds := []string{}
+	for _, d := range out {
+		kinds = append(kinds, d.Kind())
+	}
+	if !reflect.DeepEqual(kinds, []string{"overpaid", "underpaid", "free"}) {
+		t.Logf("Unexpected kinds: %v", kinds)
+		t.Fail()
+	}
+
+	// The upgrades not applied are free by default, which isn't an explicit
+	// cost.
+	sheet, err := ParseSheet(strings.NewReader(`Name: Test
+
+WS 30
+
+2015/07/01 Creation [1000]
++ Awareness
+* WS +5
+- Awareness
++ Catfall [100]
+`))
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	universe := Universe{
+		Characteristics: []Characteristic{{Name: "WS"}},
+		Skills:          []Skill{{Name: "Awareness"}, {Name: "Dodge"}},
+		Talents:         []Talent{{Name: "Catfall", Tier: 1}},
+		Costs: CostMatrix{
+			"characteristic": {0: {1: 100}},
+			"skill":          {0: {1: 200, 2: 400}},
+			"talent":         {0: {1: 300}},
+		},
+	}
+	character, err := NewCharacter(universe, sheet, Options{})
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	names := []string{}
+	for _, d := range character.Audit() {
+		names = append(names, d.Record.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Catfall"}) {
+		t.Logf("Unexpected discrepancies on the sheet: %v", names)
+		t.Fail()
+	}
+}
@@ -1,15 +1,28 @@
 package main
 
+import (
+	"strings"
+)
+
 // Background represents an element providing traits to a character.
 type Background struct {
 	Type     string   `yaml:"type"`
 	Name     string   `yaml:"name"`
 	Upgrades []string `yaml:"upgrades"`
+	Meta     Meta     `yaml:"-"`
+
+	// Generation overrides the generation method of the characteristics of
+	// the universe, if any.
+	Generation *Generation `yaml:"generation"`
 }
 
 // Apply changes the character's trait according to the history values
 func (b Background) Apply(character *Character, universe Universe) error {
 
+	// The options of the background in the header replace the duplicate
+	// aptitudes, in order.
+	options := b.Meta.Options
+
 	// For each upgrade associated to the history, apply each option.
 	for _, raw := range b.Upgrades {
 
@@ -17,16 +30,57 @@ func (b Background) Apply(character *Character, universe Universe) error {
 			Mark: MarkApply,
 			Name: raw,
 			Cost: IntP(0),
+			Line: b.Meta.Line,
+			File: b.Meta.File,
 		}
 		_, found := universe.FindCharacteristic(upgrade)
 		if found {
 			upgrade.Mark = MarkSpecial
 		}
 
+		// An aptitude the character already has follows the duplicate
+		// aptitudes policy of the universe.
+		var substituted string
+		if aptitude, found := universe.FindAptitude(upgrade); found {
+			if _, owned := character.Aptitudes[string(aptitude)]; owned {
+				policy := universe.DuplicateAptitudes
+				switch policy.Policy {
+				case PolicyError:
+					return NewError(DuplicateAptitude, b.Meta.Position(), aptitude, b.Name)
+
+				case PolicySubstitute:
+					if len(options) == 0 {
+						return NewError(MissingAptitudeSubstitute, b.Meta.Position(), aptitude, b.Name)
+					}
+					substitute, found := universe.FindAptitude(Upgrade{Name: options[0]})
+					if !found || !policy.Allows(substitute) {
+						return NewError(InvalidAptitudeSubstitute, b.Meta.Position(), options[0], aptitude)
+					}
+					if _, owned := character.Aptitudes[string(substitute)]; owned {
+						return NewError(InvalidAptitudeSubstitute, b.Meta.Position(), options[0], aptitude)
+					}
+					options = options[1:]
+					upgrade.Name = string(substitute)
+					substituted = string(aptitude)
+
+				default:
+					character.Warnings = append(character.Warnings, NewError(IgnoredDuplicateAptitude, b.Meta.Position(), aptitude, b.Name))
+					continue
+				}
+			}
+		}
+
 		err := character.ApplyUpgrade(upgrade, universe)
 		if err != nil {
 			return err
 		}
+		character.History[len(character.History)-1].Background = b.Name
+		character.History[len(character.History)-1].Substitute = substituted
+	}
+
+	// The options not used as replacements are likely mistakes.
+	if len(options) != 0 {
+		character.Warnings = append(character.Warnings, NewError(UnusedAptitudeSubstitutes, b.Meta.Position(), strings.Join(options, ", "), b.Name))
 	}
 
 	// Add the background to the character's backgrounds
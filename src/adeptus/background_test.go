@@ -80,10 +80,10 @@ func Test_Background_Apply(t *testing.T) {
 				Aptitudes: map[string]Aptitude{
 					"awesomeness": Aptitude("awesomeness"),
 				},
-				History: []Upgrade{
-					Upgrade{Mark: MarkApply, Name: "awesomeness", Cost: IntP(0)},
-					Upgrade{Mark: MarkApply, Name: "blacchusness", Cost: IntP(0)},
-					Upgrade{Mark: MarkSpecial, Name: "WS +5", Cost: IntP(0)},
+				History: []Record{
+					{Upgrade: Upgrade{Mark: MarkApply, Name: "awesomeness", Cost: IntP(0)}, Type: "aptitude", Explicit: true, Computed: IntP(0), Background: "france"},
+					{Upgrade: Upgrade{Mark: MarkApply, Name: "blacchusness", Cost: IntP(0)}, Type: "talent", Tier: 1, Explicit: true, Background: "france"},
+					{Upgrade: Upgrade{Mark: MarkSpecial, Name: "WS +5", Cost: IntP(0)}, Type: "characteristic", Background: "france"},
 				},
 			},
 			err: false,
@@ -143,3 +143,126 @@ func Test_Background_Apply(t *testing.T) {
 		}
 	}
 }
+
+func Test_Background_Apply_DuplicateAptitude(t *testing.T) {
+	background := Background{
+		Type:     "role",
+		Name:     "seeker",
+		Upgrades: []string{"Perception", "Fellowship"},
+	}
+
+	cases := []struct {
+		policy    AptitudePolicy
+		options   []string
+		aptitudes map[string]Aptitude
+		warnings  int
+		err       bool
+		code      ErrorCode
+	}{
+		{
+			policy: AptitudePolicy{},
+			aptitudes: map[string]Aptitude{
+				"Perception": "Perception",
+				"Fellowship": "Fellowship",
+			},
+			warnings: 1,
+		},
+		{
+			policy: AptitudePolicy{Policy: PolicyError},
+			err:    true,
+			code:   DuplicateAptitude,
+		},
+		{
+			policy: AptitudePolicy{Policy: PolicySubstitute},
+			err:    true,
+			code:   MissingAptitudeSubstitute,
+		},
+		{
+			policy:  AptitudePolicy{Policy: PolicySubstitute, Substitutes: []Aptitude{"Willpower"}},
+			options: []string{"agility"},
+			err:     true,
+			code:    InvalidAptitudeSubstitute,
+		},
+		{
+			policy:  AptitudePolicy{Policy: PolicySubstitute},
+			options: []string{"Perception"},
+			err:     true,
+			code:    InvalidAptitudeSubstitute,
+		},
+		{
+			policy:  AptitudePolicy{Policy: PolicySubstitute, Substitutes: []Aptitude{"Agility", "Willpower"}},
+			options: []string{"agility"},
+			aptitudes: map[string]Aptitude{
+				"Perception": "Perception",
+				"Agility":    "Agility",
+				"Fellowship": "Fellowship",
+			},
+		},
+		{
+			policy:  AptitudePolicy{Policy: PolicySubstitute},
+			options: []string{"agility", "willpower"},
+			aptitudes: map[string]Aptitude{
+				"Perception": "Perception",
+				"Agility":    "Agility",
+				"Fellowship": "Fellowship",
+			},
+			warnings: 1,
+		},
+		{
+			policy:  AptitudePolicy{},
+			options: []string{"agility"},
+			aptitudes: map[string]Aptitude{
+				"Perception": "Perception",
+				"Fellowship": "Fellowship",
+			},
+			warnings: 2,
+		},
+	}
+
+	for i, c := range cases {
+		universe := Universe{
+			Aptitudes:          []Aptitude{"Perception", "Fellowship", "Agility", "Willpower"},
+			DuplicateAptitudes: c.policy,
+		}
+		character := Character{
+			Backgrounds: map[string]Background{},
+			Aptitudes: map[string]Aptitude{
+				"Perception": "Perception",
+			},
+		}
+
+		b := background
+		b.Meta = Meta{Label: "Seeker", Options: c.options, Line: 2}
+		err := b.Apply(&character, universe)
+		if (err != nil) != c.err {
+			if c.err {
+				t.Logf("Expected error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+			continue
+		} else if err != nil {
+			code := err.(Error).Code
+			if c.code != code {
+				t.Logf("Unexpected error on case %d:", i+1)
+				t.Logf("	Expected %s", NewError(c.code))
+				t.Logf("	Having %s", err)
+				t.Fail()
+			}
+			continue
+		}
+
+		if len(character.Warnings) != c.warnings {
+			t.Logf("Unexpected warnings on case %d: %v", i+1, character.Warnings)
+			t.Fail()
+		}
+
+		if !reflect.DeepEqual(character.Aptitudes, c.aptitudes) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.aptitudes)
+			t.Logf("	Having %v", character.Aptitudes)
+			t.Fail()
+		}
+	}
+}
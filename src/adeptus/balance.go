@@ -0,0 +1,11 @@
+package main
+
+// Balance is the experience earned and spent by the character during a
+// session, along with the experience remaining at its end.
+type Balance struct {
+	Session   Session
+	Earned    int
+	Spent     int
+	Remaining int
+	Upgrades  []Record
+}
@@ -10,57 +10,228 @@ import (
 
 // Bootstrap open and parse universe and character sheet.
 func Bootstrap(ctx *cli.Context) (Universe, *Character, error) {
+	universe, sheet, err := BootstrapSheet(ctx)
+	if err != nil {
+		return Universe{}, nil, err
+	}
+
+	// Create character with the sheet
+	character, err := NewCharacter(universe, sheet, CompileOptions(ctx))
+	if err != nil {
+		return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("unable to create character:"), Render(err))
+	}
+	PrintWarnings(character.Warnings)
+
+	return universe, character, nil
+}
+
+// BootstrapSheet open and parse universe and character sheet, completing the
+// sheet with the campaign log.
+func BootstrapSheet(ctx *cli.Context) (Universe, Sheet, error) {
 	// Open and parse the universe
-	files, err := filepath.Glob(ctx.GlobalString("universe") + "/*.yaml")
+	universe, err := LoadUniverse(ctx.GlobalString("universe"))
 	if err != nil {
-		return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("unable to open universe:"), err)
+		return Universe{}, Sheet{}, err
 	}
-	
+
+	// Open and parse character sheet.
+	args := ctx.Args()
+	if len(args) == 0 {
+		return Universe{}, Sheet{}, fmt.Errorf("%s undefined character", theme.Error("unable to open character sheet:"))
+	}
+	if len(args) > 1 {
+		return Universe{}, Sheet{}, fmt.Errorf("%s too many character sheets, use the party command to compile several characters", theme.Error("unable to open character sheet:"))
+	}
+	sheet, err := LoadSheet(args[0])
+	if err != nil {
+		return Universe{}, Sheet{}, err
+	}
+
+	// Complete the sheet with the campaign log.
+	campaign, err := LoadCampaign(ctx.GlobalString("campaign"))
+	if err != nil {
+		return Universe{}, Sheet{}, err
+	}
+	sheet, warnings, err := campaign.Resolve(sheet)
+	if err != nil {
+		return Universe{}, Sheet{}, fmt.Errorf("%s %s", theme.Error("corrupted character sheet:"), Render(err))
+	}
+	PrintWarnings(warnings)
+
+	return universe, sheet, nil
+}
+
+// BootstrapParty open and parse universe and each of the given character
+// sheets.
+func BootstrapParty(ctx *cli.Context) (Universe, Party, error) {
+	// Open and parse the universe
+	universe, err := LoadUniverse(ctx.GlobalString("universe"))
+	if err != nil {
+		return Universe{}, nil, err
+	}
+
+	// Open and parse the character sheets.
+	args := ctx.Args()
+	if len(args) == 0 {
+		return Universe{}, nil, fmt.Errorf("%s undefined character", theme.Error("unable to open character sheet:"))
+	}
+	campaign, err := LoadCampaign(ctx.GlobalString("campaign"))
+	if err != nil {
+		return Universe{}, nil, err
+	}
+	sheets := []Sheet{}
+	for _, name := range args {
+		sheet, err := LoadSheet(name)
+		if err != nil {
+			return Universe{}, nil, err
+		}
+
+		// Complete the sheet with the campaign log.
+		sheet, warnings, err := campaign.Resolve(sheet)
+		if err != nil {
+			return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("corrupted character sheet:"), Render(err))
+		}
+		PrintWarnings(warnings)
+
+		sheets = append(sheets, sheet)
+	}
+
+	// Create the party with the sheets.
+	party, err := NewParty(universe, sheets, CompileOptions(ctx))
+	if err != nil {
+		return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("unable to create character:"), err)
+	}
+	for _, member := range party {
+		PrintWarnings(member.Character.Warnings)
+	}
+
+	return universe, party, nil
+}
+
+// CompileOptions returns the options of the characters' compilation given on
+// the command line.
+func CompileOptions(ctx *cli.Context) Options {
+	return Options{
+		Lenient:   ctx.GlobalBool("lenient"),
+		WarnRules: ctx.GlobalBool("warn-rules"),
+		AllowDebt: ctx.GlobalBool("allow-debt"),
+		Refund:    ctx.GlobalBool("refund"),
+	}
+}
+
+// HistoryFilter returns the filter of the history records given on the
+// command line.
+func HistoryFilter(ctx *cli.Context) (Filter, error) {
+	filter := Filter{
+		Types: ctx.StringSlice("type"),
+		Name:  ctx.String("name"),
+	}
+
+	var err error
+	if len(ctx.String("from")) != 0 {
+		filter.From, err = parseDate(ctx.String("from"))
+		if err != nil {
+			return Filter{}, fmt.Errorf("%s %s", theme.Error("invalid date:"), ctx.String("from"))
+		}
+	}
+	if len(ctx.String("to")) != 0 {
+		filter.To, err = parseDate(ctx.String("to"))
+		if err != nil {
+			return Filter{}, fmt.Errorf("%s %s", theme.Error("invalid date:"), ctx.String("to"))
+		}
+	}
+
+	return filter, nil
+}
+
+// PrintWarnings displays the given warnings.
+func PrintWarnings(warnings []error) {
+	for _, warning := range warnings {
+		fmt.Printf("%s %s\n", theme.Warning("warning:"), Render(warning))
+	}
+}
+
+// LoadUniverse open and parse every universe file of the given directory, and
+// merge them into a single universe.
+func LoadUniverse(dir string) (Universe, error) {
+	files, err := filepath.Glob(dir + "/*.yaml")
+	if err != nil {
+		return Universe{}, fmt.Errorf("%s %s", theme.Error("unable to open universe:"), err)
+	}
+
 	var universe Universe
 	for _, f := range files {
 		u, err := os.Open(f)
 		if err != nil {
-			return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("unable to open universe:"), err)
+			return Universe{}, fmt.Errorf("%s %s", theme.Error("unable to open universe:"), err)
 		}
 		defer func() {
 			_ = u.Close()
 		}()
-		tmp, err := ParseUniverse(u)
+		tmp, err := parseUniverse(u, f)
 		if err != nil {
-			return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("corrupted universe:"), err)
+			return Universe{}, fmt.Errorf("%s %s", theme.Error("corrupted universe:"), Render(err))
 		}
-		
+
 		universe, err = MergeUniverses(universe, tmp)
 		if err != nil {
-			return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("corrupted universe:"), err)
+			return Universe{}, fmt.Errorf("%s %s: %s", theme.Error("corrupted universe:"), f, err)
 		}
 	}
 
-	// Open and parse character sheet.
-	args := ctx.Args()
-	if len(args) == 0 {
-		return Universe{}, nil, fmt.Errorf("%s undefined character", theme.Error("unable to open character sheet:"))
+	// The modifiers may target the traits of any file.
+	err = universe.CheckModifiers()
+	if err != nil {
+		return Universe{}, fmt.Errorf("%s %s", theme.Error("corrupted universe:"), err)
+	}
+
+	// The generation of a background may rely on the universe's method of
+	// any file.
+	err = universe.CheckGenerations()
+	if err != nil {
+		return Universe{}, fmt.Errorf("%s %s", theme.Error("corrupted universe:"), err)
 	}
-	name := args[len(args)-1]
+
+	return universe, nil
+}
+
+// LoadSheet open and parse the character sheet at the given path.
+func LoadSheet(name string) (Sheet, error) {
 	c, err := os.Open(name)
 	if err != nil {
-		return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("unable to open character sheet:"), err)
+		return Sheet{}, fmt.Errorf("%s %s", theme.Error("unable to open character sheet:"), err)
 	}
 	defer func() {
 		_ = c.Close()
 	}()
-	sheet, err := ParseSheet(c)
+	sheet, err := parseSheet(c, name)
 	if err != nil {
-		return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("corrupted character sheet:"), err)
+		return Sheet{}, fmt.Errorf("%s %s", theme.Error("corrupted character sheet:"), Render(err))
 	}
 
-	// Create character with the sheet
-	character, err := NewCharacter(universe, sheet)
+	return sheet, nil
+}
+
+// LoadCampaign open and parse the campaign log at the given path. An empty
+// path gives an empty campaign.
+func LoadCampaign(name string) (Campaign, error) {
+	if len(name) == 0 {
+		return Campaign{}, nil
+	}
+
+	f, err := os.Open(name)
 	if err != nil {
-		return Universe{}, nil, fmt.Errorf("%s %s", theme.Error("unable to create character:"), err)
+		return Campaign{}, fmt.Errorf("%s %s", theme.Error("unable to open campaign:"), err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	campaign, err := parseCampaign(f, name)
+	if err != nil {
+		return Campaign{}, fmt.Errorf("%s %s", theme.Error("corrupted campaign:"), Render(err))
 	}
 
-	return universe, character, nil
+	return campaign, nil
 }
 
 // MergeUniverses two universes into one.
@@ -149,6 +320,89 @@ func MergeUniverses(u1, u2 Universe) (Universe, error) {
 	if u1.Costs == nil {
 		u1.Costs = u2.Costs
 	}
+
+	// Merge alignment.
+	if u1.Alignment != nil && u2.Alignment != nil {
+		return Universe{}, fmt.Errorf("alignment already defined")
+	}
+	if u1.Alignment == nil {
+		u1.Alignment = u2.Alignment
+	}
+
+	// Merge careers.
+	u1.Careers = append(u1.Careers, u2.Careers...)
+	duplicates = make(map[string]struct{})
+	for _, a := range u1.Careers {
+		if _, ok := duplicates[a.Name]; ok {
+			return Universe{}, fmt.Errorf("career %s already defined", a.Name)
+		}
+		duplicates[a.Name] = struct{}{}
+	}
+
+	// Merge elite advances.
+	u1.Elites = append(u1.Elites, u2.Elites...)
+	duplicates = make(map[string]struct{})
+	for _, a := range u1.Elites {
+		if _, ok := duplicates[a.Name]; ok {
+			return Universe{}, fmt.Errorf("elite advance %s already defined", a.Name)
+		}
+		duplicates[a.Name] = struct{}{}
+	}
+
+	// Merge rules.
+	u1.Rules = append(u1.Rules, u2.Rules...)
+	duplicates = make(map[string]struct{})
+	for _, a := range u1.Rules {
+		if _, ok := duplicates[a.Name]; ok {
+			return Universe{}, fmt.Errorf("rule %s already defined", a.Name)
+		}
+		duplicates[a.Name] = struct{}{}
+	}
+
+	// Merge conditions.
+	u1.Conditions = append(u1.Conditions, u2.Conditions...)
+	duplicates = make(map[string]struct{})
+	for _, a := range u1.Conditions {
+		if _, ok := duplicates[a.Name]; ok {
+			return Universe{}, fmt.Errorf("condition %s already defined", a.Name)
+		}
+		duplicates[a.Name] = struct{}{}
+	}
+
+	// Merge generation method.
+	if len(u1.Generation.Method) != 0 && len(u2.Generation.Method) != 0 {
+		return Universe{}, fmt.Errorf("generation method already defined")
+	}
+	if len(u1.Generation.Method) == 0 {
+		u1.Generation = u2.Generation
+	}
+
+	// Merge refunded fraction.
+	if u1.Refund != nil && u2.Refund != nil {
+		return Universe{}, fmt.Errorf("refund already defined")
+	}
+	if u1.Refund == nil {
+		u1.Refund = u2.Refund
+	}
+
+	// Merge duplicate aptitudes policy.
+	if len(u1.DuplicateAptitudes.Policy) != 0 && len(u2.DuplicateAptitudes.Policy) != 0 {
+		return Universe{}, fmt.Errorf("duplicate aptitudes policy already defined")
+	}
+	if len(u1.DuplicateAptitudes.Policy) == 0 {
+		u1.DuplicateAptitudes = u2.DuplicateAptitudes
+	}
+
+	// Merge cost rules.
+	for typ, rule := range u2.CostRules {
+		if u1.CostRules == nil {
+			u1.CostRules = make(map[string]CostRule)
+		}
+		if _, ok := u1.CostRules[typ]; ok {
+			return Universe{}, fmt.Errorf("cost rule %s already defined", typ)
+		}
+		u1.CostRules[typ] = rule
+	}
 	
 	return u1, nil
 }
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Campaign is the log of the sessions played by a party. It holds the date,
+// title and reward of each session once, so the character sheets can
+// reference them instead of repeating them.
+type Campaign struct {
+	Sessions []Session
+}
+
+// ParseCampaign parse a Campaign from a io.Reader. Each non-empty line of the
+// log is a session headline.
+func ParseCampaign(file io.Reader) (Campaign, error) {
+	return parseCampaign(file, "")
+}
+
+// parseCampaign parse a Campaign from a io.Reader read from the given file
+// name.
+func parseCampaign(file io.Reader, name string) (Campaign, error) {
+	scanner := bufio.NewScanner(file)
+	sessions := []Session{}
+
+	// Scan each line.
+	i := 0
+	for scanner.Scan() {
+		i++
+		l := line{
+			File:   name,
+			Number: i,
+			Text:   scanner.Text(),
+		}
+
+		// Discard commented and empty lines.
+		l.Text = l.Instruction()
+		if l.IsEmpty() {
+			continue
+		}
+
+		// References are meaningless in the campaign log.
+		if strings.HasPrefix(strings.TrimSpace(l.Text), ReferenceMark) {
+			return Campaign{}, NewError(ForbidenCampaignReference, l.Locate(ReferenceMark))
+		}
+
+		session, err := parseSession([]line{l})
+		if err != nil {
+			return Campaign{}, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	// In case of error, return now
+	if scanner.Err() != nil {
+		panic(fmt.Sprintf("unable to read campaign: %s", scanner.Err()))
+	}
+
+	return Campaign{
+		Sessions: sessions,
+	}, nil
+}
+
+// Find returns the sessions of the log matching the reference, either by
+// date or by title.
+func (c Campaign) Find(reference string) []Session {
+	matches := []Session{}
+
+	date, err := parseDate(reference)
+	for _, session := range c.Sessions {
+		if err == nil && session.Date.Equal(date) {
+			matches = append(matches, session)
+			continue
+		}
+
+		if err != nil && strings.EqualFold(session.Title, reference) {
+			matches = append(matches, session)
+		}
+	}
+
+	return matches
+}
+
+// Resolve fills the sessions of the sheet with the informations of the
+// campaign log: the referencing sessions get their date, title and reward,
+// and the dated sessions get their reward if they don't define one. It
+// returns the list of inconsistencies between the sheet and the log as
+// warnings.
+func (c Campaign) Resolve(sheet Sheet) (Sheet, []error, error) {
+	warnings := []error{}
+
+	sessions := make([]Session, len(sheet.Sessions))
+	copy(sessions, sheet.Sessions)
+
+	for i, session := range sessions {
+
+		// Find the session of the log corresponding to the sheet's session.
+		var matches []Session
+		if len(session.Reference) != 0 {
+			matches = c.Find(session.Reference)
+		} else {
+			// Several sessions may be played the same day, so keep the one
+			// with the same title.
+			for _, s := range c.Sessions {
+				if s.Date.Equal(session.Date) {
+					matches = append(matches, s)
+				}
+			}
+			if len(matches) > 1 {
+				titled := []Session{}
+				for _, s := range matches {
+					if strings.EqualFold(s.Title, session.Title) {
+						titled = append(titled, s)
+					}
+				}
+				if len(titled) != 0 {
+					matches = titled
+				}
+			}
+		}
+
+		if len(matches) > 1 {
+			reference := session.Reference
+			if len(reference) == 0 {
+				reference = session.Date.Format("2006/01/02")
+			}
+			return Sheet{}, nil, NewError(AmbiguousCampaignSession, session.Position(), reference)
+		}
+
+		if len(matches) == 0 {
+			// A reference must always be resolved.
+			if len(session.Reference) != 0 {
+				return Sheet{}, nil, NewError(UndefinedCampaignSession, session.Position(), session.Reference)
+			}
+
+			// Sessions outside of the log are only worth a warning if there
+			// is a log.
+			if len(c.Sessions) != 0 {
+				warnings = append(warnings, NewError(MissingCampaignSession, session.Position()))
+			}
+			continue
+		}
+		match := matches[0]
+
+		// Copy the session informations from the log.
+		if len(session.Reference) != 0 {
+			session.Date = match.Date
+			session.Title = match.Title
+		}
+
+		// Use the reward of the log if the sheet doesn't define one, and
+		// check they are the same otherwise.
+		switch {
+		case session.Reward == nil:
+			session.Reward = match.Reward
+		case match.Reward == nil:
+			warnings = append(warnings, NewError(CampaignRewardMismatch, session.Position(), *session.Reward, 0))
+		case *session.Reward != *match.Reward:
+			warnings = append(warnings, NewError(CampaignRewardMismatch, session.Position(), *session.Reward, *match.Reward))
+		}
+
+		sessions[i] = session
+	}
+
+	sheet.Sessions = sessions
+	return sheet, warnings, nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ParseCampaign(t *testing.T) {
+	cases := []struct {
+		in   string
+		out  Campaign
+		err  bool
+		code ErrorCode
+	}{
+		{
+			in: ``,
+			out: Campaign{
+				Sessions: []Session{},
+			},
+			err: false,
+		},
+		{
+			in:  `fail`,
+			out: Campaign{},
+			err: true,
+		},
+		{
+			in:   `@ First scenario`,
+			out:  Campaign{},
+			err:  true,
+			code: ForbidenCampaignReference,
+		},
+		{
+			in: `# Campaign log
+2015/07/01 Creation [1500]
+
+2015/07/08 First scenario [750] // Reward was generous
+`,
+			out: Campaign{
+				Sessions: []Session{
+					{
+						Date:     time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC),
+						Title:    "Creation",
+						Reward:   IntP(1500),
+						Upgrades: []Upgrade{},
+						Line:     2,
+					},
+					{
+						Date:     time.Date(2015, time.July, 8, 0, 0, 0, 0, time.UTC),
+						Title:    "First scenario",
+						Reward:   IntP(750),
+						Upgrades: []Upgrade{},
+						Line:     4,
+					},
+				},
+			},
+			err: false,
+		},
+	}
+
+	for i, c := range cases {
+		out, err := ParseCampaign(strings.NewReader(c.in))
+		if (err != nil) != c.err {
+			if err == nil {
+				t.Logf("Expected error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+		} else if e, ok := err.(Error); ok && c.code != 0 && e.Code != c.code {
+			t.Logf("Unexpected error on case %d:", i+1)
+			t.Logf("	Expected %s", NewError(c.code))
+			t.Logf("	Having %s", err)
+			t.Fail()
+		}
+
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Campaign_Resolve(t *testing.T) {
+	first := time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2015, time.July, 8, 0, 0, 0, 0, time.UTC)
+
+	campaign := Campaign{
+		Sessions: []Session{
+			{Date: first, Title: "Creation", Reward: IntP(1500)},
+			{Date: second, Title: "First scenario", Reward: IntP(750)},
+			{Date: second, Title: "Second scenario", Reward: IntP(250)},
+		},
+	}
+
+	cases := []struct {
+		campaign Campaign
+		in       []Session
+		out      []Session
+		warnings int
+		err      bool
+		code     ErrorCode
+	}{
+		{
+			campaign: campaign,
+			in: []Session{
+				{Reference: "creation", Line: 1},
+				{Reference: "2015/07/01", Line: 2},
+				{Date: second, Title: "Second scenario", Line: 3},
+			},
+			out: []Session{
+				{Date: first, Title: "Creation", Reward: IntP(1500), Reference: "creation", Line: 1},
+				{Date: first, Title: "Creation", Reward: IntP(1500), Reference: "2015/07/01", Line: 2},
+				{Date: second, Title: "Second scenario", Reward: IntP(250), Line: 3},
+			},
+			warnings: 0,
+			err:      false,
+		},
+		{
+			campaign: campaign,
+			in: []Session{
+				{Reference: "First scenario", Reward: IntP(500), Line: 1},
+				{Date: time.Date(2015, time.July, 15, 0, 0, 0, 0, time.UTC), Reward: IntP(500), Line: 2},
+			},
+			out: []Session{
+				{Date: second, Title: "First scenario", Reward: IntP(500), Reference: "First scenario", Line: 1},
+				{Date: time.Date(2015, time.July, 15, 0, 0, 0, 0, time.UTC), Reward: IntP(500), Line: 2},
+			},
+			warnings: 2,
+			err:      false,
+		},
+		{
+			campaign: campaign,
+			in: []Session{
+				{Reference: "2015/07/08", Line: 1},
+			},
+			err:  true,
+			code: AmbiguousCampaignSession,
+		},
+		{
+			campaign: campaign,
+			in: []Session{
+				{Date: second, Title: "Third scenario", Line: 1},
+			},
+			err:  true,
+			code: AmbiguousCampaignSession,
+		},
+		{
+			campaign: campaign,
+			in: []Session{
+				{Date: first, Title: "Character creation", Line: 1},
+			},
+			out: []Session{
+				{Date: first, Title: "Character creation", Reward: IntP(1500), Line: 1},
+			},
+			warnings: 0,
+			err:      false,
+		},
+		{
+			campaign: campaign,
+			in: []Session{
+				{Reference: "Third scenario", Line: 1},
+			},
+			err:  true,
+			code: UndefinedCampaignSession,
+		},
+		{
+			campaign: Campaign{},
+			in: []Session{
+				{Reference: "Creation", Line: 1},
+			},
+			err:  true,
+			code: UndefinedCampaignSession,
+		},
+		{
+			campaign: Campaign{},
+			in: []Session{
+				{Date: first, Reward: IntP(500), Line: 1},
+			},
+			out: []Session{
+				{Date: first, Reward: IntP(500), Line: 1},
+			},
+			warnings: 0,
+			err:      false,
+		},
+	}
+
+	for i, c := range cases {
+		out, warnings, err := c.campaign.Resolve(Sheet{Sessions: c.in})
+		if (err != nil) != c.err {
+			if c.err {
+				t.Logf("Expected error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+		} else if err != nil {
+			code := err.(Error).Code
+			if c.code != code {
+				t.Logf("Unexpected error on case %d:", i+1)
+				t.Logf("	Expected %s", NewError(c.code))
+				t.Logf("	Having %s", err)
+				t.Fail()
+			}
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if len(warnings) != c.warnings {
+			t.Logf("Unexpected warnings on case %d: %v", i+1, warnings)
+			t.Fail()
+		}
+
+		if !reflect.DeepEqual(out.Sessions, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out.Sessions)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"strings"
+)
+
+// CareerMeta is the header key selecting the character's career.
+const CareerMeta = "career"
+
+// Career is a path of advancement, made of ranks giving access to advances
+// at fixed costs. It replaces the aptitudes in the older systems.
+type Career struct {
+	Name  string `yaml:"name"`
+	Ranks []Rank `yaml:"ranks"`
+}
+
+// Rank is a step of a career, reached once the character spent the minimum
+// experience of the rank.
+type Rank struct {
+	Name     string    `yaml:"name"`
+	Min      int       `yaml:"min"`
+	Max      int       `yaml:"max"`
+	Advances []Advance `yaml:"advances"`
+}
+
+// Advance is an upgrade purchasable at a fixed cost. An upgrade listed
+// several times may be purchased as many times, each purchase using the
+// next advance in rank order.
+type Advance struct {
+	Name string `yaml:"name"`
+	Cost int    `yaml:"cost"`
+}
+
+// Rank returns the index of the rank of a character having spent the given
+// experience: the rank whose range contains it, or the last rank reached.
+func (c Career) Rank(spent int) int {
+	rank := 0
+	for i, r := range c.Ranks {
+		if spent < r.Min {
+			continue
+		}
+		rank = i
+		if r.Max == 0 || spent <= r.Max {
+			break
+		}
+	}
+	return rank
+}
+
+// careerSpent returns the experience spent on the purchases of the
+// character's history, which gives the rank reached in the career. The
+// upgrades granted by the backgrounds, elite advances and thresholds aren't
+// purchases, and the refunded purchases are no longer spent.
+func (c Character) careerSpent() int {
+	spent := 0
+	for _, record := range c.History {
+		if len(record.Background) != 0 || len(record.Elite) != 0 || len(record.Threshold) != 0 {
+			continue
+		}
+		if record.Refunds != nil || record.Cost == nil {
+			continue
+		}
+		spent += *record.Cost
+	}
+	return spent
+}
+
+// Cost returns the cost of the next purchase of the given trait by the
+// character, the trait being purchased for the given number of times
+// already. The purchase must be an advance of a rank the character reached,
+// the errors being located by the caller.
+func (c Career) Cost(universe Universe, character Character, typ string, name string, purchased int) (int, error) {
+	current := c.Rank(character.careerSpent())
+
+	count := 0
+	for i, rank := range c.Ranks {
+		for _, advance := range rank.Advances {
+			coster, found := universe.FindCoster(Upgrade{Name: advance.Name})
+			if !found {
+				continue
+			}
+
+			t, n := trait(coster)
+			if t != typ || !strings.EqualFold(n, name) {
+				continue
+			}
+
+			if count < purchased {
+				count++
+				continue
+			}
+
+			if i > current {
+				return 0, NewError(UnavailableAdvance, name, rank.Name, c.Name, c.Ranks[current].Name)
+			}
+
+			return advance.Cost, nil
+		}
+	}
+
+	return 0, NewError(UndefinedAdvance, name, c.Name)
+}
+
+// checkAdvance returns an error if the upgrade isn't an advance of the ranks
+// reached in the character's career, whatever its cost. The other errors of
+// pricing are left to the cost of the upgrade.
+func (c *Character) checkAdvance(upgrade Upgrade, universe Universe) error {
+	coster, found := universe.FindCoster(upgrade)
+	if !found {
+		return nil
+	}
+	_, err := coster.Cost(universe, *c)
+	if e, ok := err.(Error); ok && (e.Code == UndefinedAdvance || e.Code == UnavailableAdvance) {
+		return locate(err, upgrade)
+	}
+	return nil
+}
+
+// locate returns the career error at the position of the upgrade being
+// priced, the other errors being returned as is.
+func locate(err error, upgrade Upgrade) error {
+	e, ok := err.(Error)
+	if !ok || (e.Code != UndefinedAdvance && e.Code != UnavailableAdvance) {
+		return err
+	}
+	return NewError(e.Code, append([]interface{}{upgrade.Position()}, e.vars...)...)
+}
+
+// trait returns the type and name of the trait upgraded by the coster.
+func trait(coster Coster) (string, string) {
+	switch coster := coster.(type) {
+	case Characteristic:
+		return "characteristic", coster.Name
+	case Skill:
+		return "skill", coster.FullName()
+	case Talent:
+		return "talent", coster.FullName()
+	}
+	return "", ""
+}
+
+// FindCareer returns the career corresponding to the given name or a zero
+// value, and a boolean indicating if it was found.
+func (u Universe) FindCareer(name string) (Career, bool) {
+	for _, career := range u.Careers {
+		if strings.EqualFold(career.Name, name) {
+			return career, true
+		}
+	}
+	return Career{}, false
+}
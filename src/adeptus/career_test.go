@@ -0,0 +1,203 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_Career_Rank(t *testing.T) {
+	career := Career{
+		Name: "Explorator",
+		Ranks: []Rank{
+			{Name: "Magos", Min: 0, Max: 999},
+			{Name: "Explorator Magos", Min: 1000, Max: 1999},
+			{Name: "Archmagos", Min: 2000},
+		},
+	}
+
+	cases := []struct {
+		spent int
+		out   int
+	}{
+		{spent: 0, out: 0},
+		{spent: 999, out: 0},
+		{spent: 1000, out: 1},
+		{spent: 2500, out: 2},
+	}
+
+	for i, c := range cases {
+		out := career.Rank(c.spent)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %d", c.out)
+			t.Logf("	Having %d", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_NewCharacter_Career(t *testing.T) {
+	universe := Universe{
+		Characteristics: []Characteristic{
+			{Name: "WS"},
+		},
+		Skills: []Skill{
+			{Name: "Awareness"},
+			{Name: "Dodge"},
+		},
+		Talents: []Talent{
+			{Name: "Catfall"},
+		},
+		Careers: []Career{
+			{
+				Name: "Explorator",
+				Ranks: []Rank{
+					{
+						Name: "Magos",
+						Max:  499,
+						Advances: []Advance{
+							{Name: "Awareness", Cost: 100},
+							{Name: "WS +5", Cost: 100},
+						},
+					},
+					{
+						Name: "Explorator Magos",
+						Min:  500,
+						Advances: []Advance{
+							{Name: "Awareness", Cost: 200},
+							{Name: "WS +5", Cost: 250},
+							{Name: "Catfall", Cost: 300},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	header := Header{
+		Name: "Magos",
+		Metas: map[string][]Meta{
+			"career": {{Label: "explorator", Line: 2}},
+		},
+	}
+	characteristics := Characteristics{
+		{Mark: MarkSpecial, Name: "WS 30", Cost: IntP(0), Line: 4},
+	}
+
+	cases := []struct {
+		upgrades []Upgrade
+		spent    int
+		rank     string
+		err      bool
+		code     ErrorCode
+		line     int
+	}{
+		{
+			upgrades: []Upgrade{
+				{Mark: MarkApply, Name: "Awareness", Line: 7},
+				{Mark: MarkApply, Name: "WS +5", Line: 8},
+				{Mark: MarkApply, Name: "Awareness", Line: 9},
+			},
+			err:  true,
+			code: UnavailableAdvance,
+			line: 9,
+		},
+		{
+			upgrades: []Upgrade{
+				{Mark: MarkApply, Name: "Awareness", Line: 7},
+				{Mark: MarkApply, Name: "Rule: Patron", Cost: IntP(400), Line: 8},
+				{Mark: MarkApply, Name: "Awareness", Line: 9},
+				{Mark: MarkApply, Name: "WS +5", Line: 10},
+				{Mark: MarkApply, Name: "WS +5", Line: 11},
+				{Mark: MarkApply, Name: "Catfall", Line: 12},
+			},
+			spent: 1350,
+			rank:  "Explorator Magos",
+		},
+		{
+			upgrades: []Upgrade{
+				{Mark: MarkApply, Name: "Dodge", Line: 7},
+			},
+			err:  true,
+			code: UndefinedAdvance,
+			line: 7,
+		},
+		{
+			upgrades: []Upgrade{
+				{Mark: MarkApply, Name: "Dodge", Cost: IntP(150), Line: 7},
+			},
+			err:  true,
+			code: UndefinedAdvance,
+			line: 7,
+		},
+		{
+			upgrades: []Upgrade{
+				{Mark: MarkApply, Name: "Catfall", Cost: IntP(300), Line: 7},
+			},
+			err:  true,
+			code: UnavailableAdvance,
+			line: 7,
+		},
+		{
+			upgrades: []Upgrade{
+				{Mark: MarkApply, Name: "Awareness", Cost: IntP(50), Line: 7},
+			},
+			spent: 50,
+			rank:  "Magos",
+		},
+	}
+
+	for i, c := range cases {
+		sheet := Sheet{
+			Header:          header,
+			Characteristics: characteristics,
+			Sessions: []Session{
+				{Reward: IntP(2000), Upgrades: c.upgrades, Line: 6},
+			},
+		}
+
+		out, err := NewCharacter(universe, sheet, Options{})
+		if (err != nil) != c.err {
+			if c.err {
+				t.Logf("Expected error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+			continue
+		} else if err != nil {
+			code := err.(Error).Code
+			position, _ := err.(Error).Position()
+			if c.code != code || position.Line != c.line {
+				t.Logf("Unexpected error on case %d:", i+1)
+				t.Logf("	Expected %s", NewError(c.code))
+				t.Logf("	Having %s", err)
+				t.Fail()
+			}
+			continue
+		}
+
+		if out.Spent != c.spent || out.Rank != c.rank || out.Career != "Explorator" {
+			t.Logf("Unexpected output on case %d: %d spent as %s %s", i+1, out.Spent, out.Career, out.Rank)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Character_careerSpent(t *testing.T) {
+	purchase := Upgrade{Mark: MarkApply, Name: "Awareness", Cost: IntP(200)}
+	c := Character{
+		History: []Record{
+			{Upgrade: Upgrade{Mark: MarkApply, Name: "Catfall", Cost: IntP(0)}, Background: "Feral World"},
+			{Upgrade: Upgrade{Mark: MarkApply, Name: "Dodge", Cost: IntP(300)}},
+			{Upgrade: Upgrade{Mark: MarkApply, Name: "Rule: Patron", Cost: IntP(400)}},
+			{Upgrade: Upgrade{Mark: MarkApply, Name: "Iron Jaw", Cost: IntP(0)}, Elite: "Psyker"},
+			{Upgrade: Upgrade{Mark: MarkRevert, Name: "Awareness", Cost: IntP(-100)}, Refunds: &purchase},
+		},
+		Spent: 800,
+	}
+
+	if out := c.careerSpent(); out != 700 {
+		t.Logf("Unexpected career experience: expected 700, having %d", out)
+		t.Fail()
+	}
+}
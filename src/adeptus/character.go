@@ -20,13 +20,44 @@ type Character struct {
 	Gauges          map[string]Gauge
 	Rules           map[string]Rule
 	Spells          map[string]Spell
+	Elites          map[string]Elite
+	Conditions      map[string]Effect
+	Alignment       string
+	Career          string
+	Rank            string
 	Experience      int
 	Spent           int
-	History         []Upgrade
+	History         []Record
+	Balances        []Balance
+	Crossings       []Crossing
+	Warnings        []error
+	options         Options
+	pending         []Crossing
+	session         int
+	refunded        int
+}
+
+// Options holds the settings of the character's compilation.
+type Options struct {
+	// Lenient turns the sheet's upgrades undefined in the universe into
+	// special rules instead of errors.
+	Lenient bool
+
+	// WarnRules reports each upgrade turned into a special rule because it
+	// isn't defined in the universe.
+	WarnRules bool
+
+	// AllowDebt reports the upgrades spending more experience than earned
+	// as warnings instead of errors.
+	AllowDebt bool
+
+	// Refund turns the reverted upgrades into refunds of the matching
+	// earlier purchases.
+	Refund bool
 }
 
 // NewCharacter creates a new character from the given sheet and universe.
-func NewCharacter(universe Universe, sheet Sheet) (*Character, error) {
+func NewCharacter(universe Universe, sheet Sheet, options Options) (*Character, error) {
 
 	// Create a character
 	c := Character{
@@ -39,8 +70,11 @@ func NewCharacter(universe Universe, sheet Sheet) (*Character, error) {
 		Gauges:          make(map[string]Gauge),
 		Rules:           make(map[string]Rule),
 		Spells:          make(map[string]Spell),
+		Elites:          make(map[string]Elite),
+		Conditions:      make(map[string]Effect),
 		Experience:      0,
 		Spent:           0,
+		options:         options,
 	}
 
 	// The characteristics described in the header of the sheet are parsed as upgrades
@@ -49,13 +83,13 @@ func NewCharacter(universe Universe, sheet Sheet) (*Character, error) {
 		// Get the characteristic from the universe
 		characteristic, found := universe.FindCharacteristic(upgrade)
 		if !found {
-			return nil, NewError(UndefinedCharacteristic, upgrade.Line)
+			return nil, NewError(UndefinedCharacteristic, upgrade.Position())
 		}
 
 		// Check it is not already applied
 		_, found = c.Characteristics[characteristic.Name]
 		if found {
-			return nil, NewError(DuplicateUpgrade, upgrade.Line)
+			return nil, NewError(DuplicateUpgrade, upgrade.Position())
 		}
 
 		// Apply the upgrade
@@ -65,44 +99,188 @@ func NewCharacter(universe Universe, sheet Sheet) (*Character, error) {
 		}
 	}
 
-	// Next are the backgrounds
+	// Check the block contains each characteristic of the universe.
+	var missing []string
+	for _, characteristic := range universe.Characteristics {
+		if _, found := c.Characteristics[characteristic.Name]; !found {
+			missing = append(missing, characteristic.Name)
+		}
+	}
+	if len(missing) != 0 {
+		var position Position
+		if len(sheet.Characteristics) != 0 {
+			position = sheet.Characteristics[len(sheet.Characteristics)-1].Position()
+		}
+		return nil, NewError(MissingCharacteristics, position, strings.Join(missing, ", "))
+	}
+
+	// Next are the backgrounds, in the order of the header, so that the
+	// duplicate aptitudes are always given by the same backgrounds.
+	type entry struct {
+		typ   string
+		index int
+		meta  Meta
+	}
+	entries := []entry{}
 	for typ, metas := range sheet.Header.Metas {
+		for i, meta := range metas {
+			entries = append(entries, entry{typ: typ, index: i, meta: meta})
+		}
+	}
+	slice.Sort(entries, func(i, j int) bool {
+		if entries[i].meta.Line != entries[j].meta.Line {
+			return entries[i].meta.Line < entries[j].meta.Line
+		}
+		if entries[i].typ != entries[j].typ {
+			return entries[i].typ < entries[j].typ
+		}
+		return entries[i].index < entries[j].index
+	})
 
-		for _, meta := range metas {
+	applied := []Background{}
+	for _, e := range entries {
+		typ, meta := e.typ, e.meta
 
-			// Find the background corresponding to the meta
-			background, found := universe.FindBackground(typ, meta.Label)
+		// The career is a special meta if the universe defines careers.
+		if strings.EqualFold(typ, CareerMeta) && len(universe.Careers) != 0 {
+			career, found := universe.FindCareer(meta.Label)
 			if !found {
-				return nil, NewError(UndefinedBackground, meta.Line, typ, meta.Label)
+				return nil, NewError(UndefinedCareer, meta.Position(), meta.Label)
 			}
+			c.Career = career.Name
+			continue
+		}
 
-			err := background.Apply(&c, universe)
-			if err != nil {
-				return nil, err
-			}
+		// Find the background corresponding to the meta
+		background, found := universe.FindBackground(typ, meta.Label)
+		if !found {
+			return nil, NewError(UndefinedBackground, meta.Position(), typ, meta.Label)
+		}
+
+		background.Meta = meta
+		err := background.Apply(&c, universe)
+		if err != nil {
+			return nil, err
 		}
+		applied = append(applied, background)
 	}
 
+	// The characteristics must be obtainable by the generation method of the
+	// backgrounds.
+	c.Warnings = append(c.Warnings, universe.GenerationOf(applied).Validate(sheet.Characteristics)...)
+
 	// Next are the sessions
+	debt := false
 	for _, session := range sheet.Sessions {
+		balance := Balance{
+			Session: session,
+		}
+
+		// The recovered tracks of the gauges are reset
+		for name, gauge := range c.Gauges {
+			c.Gauges[name] = gauge.Recover()
+		}
+
+		// The expired conditions are removed
+		c.session++
+		records := len(c.History)
+		c.expire(session)
+		balance.Upgrades = append(balance.Upgrades, c.History[records:]...)
 
 		// Apply the experience gain if needed
 		if session.Reward != nil {
 			c.Experience += *session.Reward
+			balance.Earned = *session.Reward
 		}
 
 		// Apply each upgrade in order
 		for _, upgrade := range session.Upgrades {
+
+			// Unless lenient, the upgrades of the sheet must be defined in
+			// the universe, the special rules being explicit.
+			if _, found := universe.FindCoster(upgrade); !found && !options.Lenient {
+				return nil, NewError(UndefinedUpgrade, upgrade.Position(), upgrade.Name, suggestions(universe.Suggest(upgrade)))
+			}
+
+			// The career restricts the purchases to the advances of the
+			// ranks reached, even when the sheet gives their cost.
+			if upgrade.Mark == MarkApply && upgrade.Cost != nil {
+				err := c.checkAdvance(upgrade, universe)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			spent, records, refunded := c.Spent, len(c.History), c.refunded
 			err := c.ApplyUpgrade(upgrade, universe)
 			if err != nil {
 				return nil, err
 			}
+
+			// The purchases refunded are removed from the history, and from
+			// the balance of their session.
+			if c.refunded != refunded {
+				records--
+				spent -= c.unbalance(*c.History[len(c.History)-1].Refunds, &balance)
+			}
+			balance.Spent += c.Spent - spent
+			balance.Upgrades = append(balance.Upgrades, c.History[records:]...)
+
+			// Check the character could afford the upgrade, reporting only
+			// the upgrade that put it in debt.
+			if c.Spent <= c.Experience {
+				debt = false
+				continue
+			}
+			if debt {
+				continue
+			}
+			debt = true
+
+			overspent := NewError(OverspentExperience, upgrade.Position(), c.Spent, c.Experience)
+			if !options.AllowDebt {
+				return nil, overspent
+			}
+			c.Warnings = append(c.Warnings, overspent)
 		}
+
+		// The rules required by the thresholds crossed during the session
+		// must follow in the same session.
+		if len(c.pending) != 0 {
+			return nil, c.missingFollowUp()
+		}
+
+		balance.Remaining = c.Experience - c.Spent
+		c.Balances = append(c.Balances, balance)
+	}
+	if len(c.pending) != 0 {
+		return nil, c.missingFollowUp()
+	}
+
+	// The rank reached in the career depends on the experience spent.
+	if career, found := universe.FindCareer(c.Career); found && len(career.Ranks) != 0 {
+		c.Rank = career.Ranks[career.Rank(c.careerSpent())].Name
 	}
 
 	return &c, nil
 }
 
+// missingFollowUp returns the error of the first rule required by a
+// threshold and still missing.
+func (c *Character) missingFollowUp() error {
+	crossing := c.pending[0]
+	return NewError(MissingFollowUp, crossing.Upgrade.Position(), crossing.Threshold.Require, crossing.String())
+}
+
+// suggestions returns the end of the undefined upgrade error message listing
+// the given suggestions.
+func suggestions(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %s?", strings.Join(names, ", "))
+}
+
 // Intersect return the number of aptitudes of the given slice
 // that are in the character's aptitudes.
 func (c *Character) Intersect(aptitudes []Aptitude) int {
@@ -126,152 +304,229 @@ func (c *Character) ApplyUpgrade(upgrade Upgrade, universe Universe) error {
 		coster = Rule{
 			Name: upgrade.Name,
 		}
+
+		if c.options.WarnRules {
+			c.Warnings = append(c.Warnings, NewError(ImplicitRule, upgrade.Position(), upgrade.Name))
+		}
+	}
+
+	// Compute the cost of the upgrade at this point, and use it if no cost
+	// is defined. The computed cost of an explicitly priced upgrade is only
+	// kept for the record. The upgrades not applied are free unless the
+	// sheet gives them a cost, so their default cost isn't explicit.
+	priced := upgrade.Cost != nil
+	explicit := priced && (upgrade.Mark == MarkApply || *upgrade.Cost != 0)
+	var computed *int
+	cost, err := coster.Cost(universe, *c)
+	if err == nil {
+		computed = &cost
+	} else if !priced {
+		return locate(err, upgrade)
 	}
 
-	// If no cost is defined, compute it on the fly.
-	if upgrade.Cost == nil {
-		cost, err := coster.Cost(universe, *c)
+	if !priced {
+		upgrade.Cost = computed
+	}
+
+	// A refund takes the reverted purchase out of the experience spent,
+	// and costs the part of it the universe doesn't give back.
+	refund, purchased := -1, 0
+	if c.options.Refund && upgrade.Mark == MarkRevert && refundable(coster) {
+		refund, err = c.purchase(upgrade)
 		if err != nil {
 			return err
 		}
-
+		purchased = *c.History[refund].Cost
+		cost := purchased - universe.Refunded(purchased)
 		upgrade.Cost = &cost
+		computed = &cost
+		explicit = false
 	}
 
-	// Apply the upgrade.
-	err := coster.Apply(c, upgrade)
-	c.Spent += *upgrade.Cost
+	// Keep the character as is, so an elite advance is granted atomically.
+	elite, isElite := coster.(Elite)
+	var snapshot Character
+	if isElite {
+		snapshot = c.clone()
+	}
+
+	// Keep the value of the gauge to detect the thresholds crossed.
+	gauge, isGauge := coster.(Gauge)
+	before := c.Gauges[gauge.Name].Value
+
+	// Apply the upgrade, or undo the refunded purchase.
+	if refund >= 0 {
+		err = c.undo(coster, c.History[refund].Upgrade, upgrade)
+	} else {
+		err = coster.Apply(c, upgrade)
+	}
+	c.Spent += *upgrade.Cost - purchased
+
+	// Track the devotion of the character to the gods of the upgrade.
+	if err == nil && universe.Alignment != nil {
+		universe.Alignment.Devote(c, universe, alignments(coster), upgrade)
+		c.Alignment = universe.Alignment.Of(*c)
+	}
 
 	// If there is no error, add the upgrade to the history.
 	if err == nil {
-		c.History = append(c.History, upgrade)
+		record := NewRecord(upgrade, coster, *c)
+		record.Explicit = explicit
+		record.Computed = computed
+		if refund >= 0 {
+			purchase := c.History[refund].Upgrade
+			record.Refunds = &purchase
+			c.History = append(c.History[:refund:refund], c.History[refund+1:]...)
+			c.refunded++
+		}
+		c.History = append(c.History, record)
+	}
+
+	// A special rule may be the follow-up required by a threshold.
+	if rule, isRule := coster.(Rule); err == nil && isRule && upgrade.Mark == MarkApply {
+		c.follow(rule)
+	}
+
+	// Apply the thresholds crossed by the gauge.
+	if err == nil && isGauge {
+		err = c.cross(gauge, before, upgrade, universe)
+	}
+
+	// Grant the upgrades of the elite advance.
+	if err == nil && isElite {
+		err = elite.Grant(c, upgrade, universe)
+		if err != nil {
+			*c = snapshot
+		}
 	}
 
 	return err
 }
 
-// Print the character sheet on the screen
-func (c *Character) Print() {
-	// Print the name
-	fmt.Printf("%s\t%s\n", theme.Title("Name"), c.Name)
+// cross records the thresholds crossed by the upgrade of the gauge, granting
+// the rules they give and waiting for the rules they require.
+func (c *Character) cross(gauge Gauge, before int, upgrade Upgrade, universe Universe) error {
+	crossings := gauge.Cross(before, c.Gauges[gauge.Name].Value, upgrade)
+	if len(crossings) == 0 {
+		return nil
+	}
 
-	// Print the backgrounds
-	backgrounds := []Background{}
+	c.History[len(c.History)-1].Crossings = crossings
+	c.Crossings = append(c.Crossings, crossings...)
 
-	for _, background := range c.Backgrounds {
-		backgrounds = append(backgrounds, background)
+	for _, crossing := range crossings {
+		if len(crossing.Threshold.Require) != 0 {
+			c.pending = append(c.pending, crossing)
+			continue
+		}
+
+		grant := Upgrade{
+			Mark: MarkApply,
+			Name: fmt.Sprintf("%s: %s", strings.Title(RulePrefix), crossing.Threshold.Grant),
+			Cost: IntP(0),
+			Line: upgrade.Line,
+			File: upgrade.File,
+		}
+		index := len(c.History)
+		err := c.ApplyUpgrade(grant, universe)
+		if err != nil {
+			return err
+		}
+		c.History[index].Threshold = crossing.String()
 	}
 
-	slice.Sort(backgrounds, func(i, j int) bool {
-		if backgrounds[i].Type != backgrounds[j].Type {
-			return backgrounds[i].Type < backgrounds[j].Type
+	return nil
+}
+
+// follow removes the first threshold waiting for the rule.
+func (c *Character) follow(rule Rule) {
+	for i, crossing := range c.pending {
+		if strings.EqualFold(crossing.Threshold.Require, rule.Name) {
+			c.pending = append(c.pending[:i:i], c.pending[i+1:]...)
+			return
 		}
+	}
+}
 
-		return backgrounds[i].Name < backgrounds[j].Name
-	})
+// Print the character sheet on the screen
+func (c *Character) Print() {
+	v := c.View()
+
+	// Print the name
+	fmt.Printf("%s\t%s\n", theme.Title("Name"), v.Name)
 
-	for _, background := range backgrounds {
+	// Print the backgrounds
+	for _, background := range v.Backgrounds {
 		fmt.Printf("%s\t%s\n", theme.Title(strings.Title(background.Type)), strings.Title(background.Name))
 	}
 
-	// Print the aptitudes
-	aptitudes := []Aptitude{}
-
-	for _, aptitude := range c.Aptitudes {
-		aptitudes = append(aptitudes, aptitude)
+	// Print the career
+	if len(v.Career) != 0 {
+		fmt.Printf("%s\t%s (%s)\n", theme.Title("Career"), v.Career, v.Rank)
 	}
 
-	slice.Sort(aptitudes, func(i, j int) bool {
-		return aptitudes[i] < aptitudes[j]
-	})
+	// Print the alignment
+	if len(v.Alignment) != 0 {
+		fmt.Printf("%s\t%s\n", theme.Title("Alignment"), v.Alignment)
+	}
 
-	fmt.Printf("\n%s (%s)\n", theme.Title("Aptitudes"), theme.Value(fmt.Sprintf("%d", len(aptitudes))))
-	for _, aptitude := range aptitudes {
+	// Print the aptitudes
+	fmt.Printf("\n%s (%s)\n", theme.Title("Aptitudes"), theme.Value(fmt.Sprintf("%d", len(v.Aptitudes))))
+	for _, aptitude := range v.Aptitudes {
 		fmt.Printf("%s\n", strings.Title(string(aptitude)))
 	}
 
 	// Print the experience
-	fmt.Printf("\n%s\t%d/%d\n", theme.Title("Experience"), c.Spent, c.Experience)
+	fmt.Printf("\n%s\t%d/%d\n", theme.Title("Experience"), v.Spent, v.Experience)
 
 	// Print the characteristics
-
-	var characteristicSum int
-	characteristics := []Characteristic{}
-	for _, characteristic := range c.Characteristics {
-        characteristicSum += characteristic.Value
-		characteristics = append(characteristics, characteristic)
-	}
-
-	slice.Sort(characteristics, func(i, j int) bool {
-		return characteristics[i].Name < characteristics[j].Name
-	})
-    
-	fmt.Printf("\n%s (%s)\n", theme.Title("Characteristics"), theme.Value(fmt.Sprintf("%d", characteristicSum)))
-
-	for _, characteristic := range characteristics {
-		fmt.Printf("%s\t%s %s\n", characteristic.Name, theme.Value(characteristic.Value), theme.Value(characteristic.Level()))
+	fmt.Printf("\n%s (%s)\n", theme.Title("Characteristics"), theme.Value(fmt.Sprintf("%d", v.CharacteristicSum())))
+	for _, characteristic := range v.Characteristics {
+		if !characteristic.Modified {
+			fmt.Printf("%s\t%s %s\n", characteristic.Name, theme.Value(characteristic.Value), theme.Value(characteristic.Level()))
+			continue
+		}
+		fmt.Printf("%s\t%s %s (%s, bonus %s)\n", characteristic.Name, theme.Value(characteristic.Value), theme.Value(characteristic.Level()), theme.Value(characteristic.Effective), theme.Value(characteristic.Bonus))
 	}
 
 	// Print the gauges
-
-	if len(c.Gauges) != 0 {
-
+	if len(v.Gauges) != 0 {
 		fmt.Printf("\n%s\n", theme.Title("Gauges"))
+		for _, gauge := range v.Gauges {
+			if !gauge.Modified {
+				fmt.Printf("%s\t%s\n", gauge.Name, theme.Value(gauge.Value))
+				continue
+			}
 
-		gauges := []Gauge{}
-		for _, gauge := range c.Gauges {
-			gauges = append(gauges, gauge)
-		}
-
-		slice.Sort(gauges, func(i, j int) bool {
-			return gauges[i].Name < gauges[j].Name
-		})
-
-		for _, gauge := range gauges {
-			fmt.Printf("%s\t%s\n", gauge.Name, theme.Value(gauge.Value))
+			details := gauge.Degradations
+			if gauge.Modifier != 0 {
+				details = append(details, fmt.Sprintf("conditions %+d", gauge.Modifier))
+			}
+			fmt.Printf("%s\t%s/%s (%s)\n", gauge.Name, theme.Value(gauge.Effective), theme.Value(gauge.Value), strings.Join(details, ", "))
 		}
 	}
 
 	// Print the skills
-
-	if len(c.Skills) != 0 {
-
-		// Print the skills using a tabwriter
+	if len(v.Skills) != 0 {
 		fmt.Printf("\n%s\n", theme.Title("Skills"))
 
-		skills := []Skill{}
-		for _, skill := range c.Skills {
-			skills = append(skills, skill)
-		}
-
-		slice.Sort(skills, func(i, j int) bool {
-			return skills[i].FullName() < skills[j].FullName()
-		})
-
 		w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
-		for _, skill := range skills {
-			fmt.Fprintf(w, "%s\t+%s\n", strings.Title(skill.FullName()), theme.Value((skill.Tier-1)*10))
+		for _, skill := range v.Skills {
+			if skill.Modifier == 0 {
+				fmt.Fprintf(w, "%s\t+%s\n", strings.Title(skill.FullName()), theme.Value(skill.Bonus))
+				continue
+			}
+			fmt.Fprintf(w, "%s\t+%s (%s)\n", strings.Title(skill.FullName()), theme.Value(skill.Bonus), theme.Value(fmt.Sprintf("%+d", skill.Effective)))
 		}
 		w.Flush()
 	}
 
 	// Print the talents
-
-	if len(c.Talents) != 0 {
-
+	if len(v.Talents) != 0 {
 		fmt.Printf("\n%s\n", theme.Title("Talents"))
 
-		talents := []Talent{}
-		for _, talent := range c.Talents {
-			talents = append(talents, talent)
-		}
-
-		slice.Sort(talents, func(i, j int) bool {
-			return talents[i].FullName() < talents[j].FullName()
-		})
-
 		w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
-		for _, talent := range talents {
+		for _, talent := range v.Talents {
 			if talent.Value != 1 {
 				fmt.Fprintf(w, "%s (%d)\t%s\n", strings.Title(talent.FullName()), talent.Value, talent.Description)
 			} else {
@@ -282,75 +537,196 @@ func (c *Character) Print() {
 	}
 
 	// Print the spells
-
-	if len(c.Spells) != 0 {
-
+	if len(v.Spells) != 0 {
 		fmt.Printf("\n%s\n", theme.Title("Spells"))
 
-		spells := []Spell{}
+		w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+		for _, spell := range v.Spells {
+			fmt.Fprintf(w, "%s\t%s\n", strings.Title(spell.Name), spell.Description)
+		}
+		w.Flush()
+	}
 
-		for _, spell := range c.Spells {
-			spells = append(spells, spell)
+	// Print the elite advances
+	if len(v.Elites) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Elite advances"))
+		for _, elite := range v.Elites {
+			fmt.Printf("%s\t%s\n", strings.Title(elite.Name), elite.Description)
 		}
+	}
 
-		slice.Sort(spells, func(i, j int) bool {
-			return spells[i].Name < spells[j].Name
-		})
+	// Print the conditions
+	if len(v.Conditions) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Conditions"))
 
 		w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
-		for _, spell := range spells {
-			fmt.Fprintf(w, "%s\t%s\n", strings.Title(spell.Name), spell.Description)
+		for _, effect := range v.Conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", strings.Title(effect.Condition.Name), effect, effect.Condition.Description)
 		}
 		w.Flush()
 	}
 
 	// Print the special rules
-
-	if len(c.Rules) != 0 {
+	if len(v.Rules) != 0 {
 		fmt.Printf("\n%s\n", theme.Title("Rules"))
+		for _, rule := range v.Rules {
+			fmt.Printf("%s\t%s\n", strings.Title(rule.Name), rule.Description)
+		}
+	}
 
-		rules := []Rule{}
+	// Print the modifiers along with their source
+	if len(v.Modifiers) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Modifiers"))
 
-		for _, rule := range c.Rules {
-			rules = append(rules, rule)
+		w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+		for _, source := range v.Modifiers {
+			fmt.Fprintf(w, "%s\t%s\t%s %s\n", strings.Title(source.Modifier.Target), theme.Value(source.Modifier), source.Type, strings.Title(source.Name))
 		}
+		w.Flush()
+	}
 
-		slice.Sort(rules, func(i, j int) bool {
-			return rules[i].Name < rules[j].Name
-		})
+	// Print the thresholds crossed
+	if len(v.Crossings) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Warnings"))
 
 		w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
-		for _, rule := range rules {
-			fmt.Printf("%s\t%s\n", strings.Title(rule.Name), rule.Description)
+		for _, crossing := range v.Crossings {
+			if len(crossing.Threshold.Require) != 0 {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", crossing, theme.Warning("requires"), strings.Title(crossing.Threshold.Require))
+			} else {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", crossing, theme.Warning("grants"), strings.Title(crossing.Threshold.Grant))
+			}
 		}
 		w.Flush()
 	}
 }
 
-// PrintHistory displays the history of expences of the character.
-func (c *Character) PrintHistory() {
+// PrintHistory displays the history of expences of the character, limited to
+// the records selected by the filter.
+func (c *Character) PrintHistory(filter Filter) {
 	// Print the name.
 	fmt.Printf("%s\t%s\n", theme.Title("Name"), c.Name)
 
 	// Print the experience
 	fmt.Printf("\n%s\t%d/%d\n", theme.Title("Experience"), c.Spent, c.Experience)
 
-	// Print the history.
-	fmt.Printf("\n%s\n", theme.Title("History"))
+	// Print the upgrades of each background, sorted as in the sheet.
+	if filter.MatchBackgrounds() {
+		backgrounds := []Background{}
+		for _, background := range c.Backgrounds {
+			backgrounds = append(backgrounds, background)
+		}
+
+		slice.Sort(backgrounds, func(i, j int) bool {
+			if backgrounds[i].Type != backgrounds[j].Type {
+				return backgrounds[i].Type < backgrounds[j].Type
+			}
+
+			return backgrounds[i].Name < backgrounds[j].Name
+		})
+
+		fmt.Printf("\n%s\n", theme.Title("Backgrounds"))
+		for _, background := range backgrounds {
+			records := []Record{}
+			for _, record := range c.History {
+				if record.Background == background.Name && filter.MatchRecord(record) {
+					records = append(records, record)
+				}
+			}
+
+			if len(records) == 0 && !filter.IsZero() {
+				continue
+			}
+
+			fmt.Printf("%s %s\n", theme.Title(strings.Title(background.Type)), strings.Title(background.Name))
+			printRecords(records)
+		}
+	}
+
+	// Print each session with its balance, followed by its upgrades.
+	fmt.Printf("\n%s\n", theme.Title("Sessions"))
+	for _, balance := range c.Balances {
+		if !filter.MatchSession(balance.Session) {
+			continue
+		}
+
+		records := []Record{}
+		for _, record := range balance.Upgrades {
+			if filter.MatchRecord(record) {
+				records = append(records, record)
+			}
+		}
+
+		if len(records) == 0 && (len(filter.Types) != 0 || len(filter.Name) != 0) {
+			continue
+		}
+
+		reward := "-"
+		if balance.Session.Reward != nil {
+			reward = fmt.Sprintf("[%d]", *balance.Session.Reward)
+		}
+
+		fmt.Printf("%s %s %s  %s %s  %s %s\n",
+			balance.Session.Date.Format("2006/01/02"),
+			balance.Session.Title,
+			theme.Value(reward),
+			theme.Title("spent"), theme.Value(balance.Spent),
+			theme.Title("balance"), theme.Value(balance.Remaining),
+		)
+		printRecords(records)
+	}
+}
 
+// printRecords displays the mark, name, type, tier reached and cost of each
+// record, telling whether the cost was explicit or computed.
+func printRecords(records []Record) {
 	w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
-	for _, upgrade := range c.History {
-		if upgrade.Cost != nil {
-			fmt.Fprintf(w, "%d\t%s\n", *upgrade.Cost, strings.Title(upgrade.Name))
-		} else {
-			fmt.Fprintf(w, "%d\t%s\n", 0, strings.Title(upgrade.Name))
+	for _, record := range records {
+		tier := "-"
+		switch record.Type {
+		case "characteristic", "skill", "talent":
+			tier = fmt.Sprintf("%d", record.Tier)
+		}
+
+		cost := 0
+		if record.Cost != nil {
+			cost = *record.Cost
+		}
+
+		origin := "computed"
+		if record.Explicit {
+			origin = "explicit"
+		}
+
+		name := strings.Title(record.Name)
+		if len(record.Substitute) != 0 {
+			name = fmt.Sprintf("%s (instead of %s)", name, strings.Title(record.Substitute))
 		}
+		if record.Expired {
+			name = fmt.Sprintf("%s (expired)", name)
+		}
+		if record.Refunds != nil {
+			name = fmt.Sprintf("%s (refunds %s)", name, record.Refunds.Position())
+		}
+		if len(record.Threshold) != 0 {
+			name = fmt.Sprintf("%s (granted by %s)", name, record.Threshold)
+		}
+		if len(record.Crossings) != 0 {
+			crossings := []string{}
+			for _, crossing := range record.Crossings {
+				crossings = append(crossings, crossing.String())
+			}
+			name = fmt.Sprintf("%s (crosses %s)", name, strings.Join(crossings, ", "))
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%d\t%s\n", record.Mark, name, record.Type, tier, cost, origin)
 	}
 	w.Flush()
 }
 
-// Suggest the next purchasable upgrades of the character.
-func (c *Character) Suggest(universe Universe, max int, all bool, allowSpells bool) {
+// Suggestions returns the next purchasable upgrades of the character, sorted
+// by cost then name.
+func (c *Character) Suggestions(universe Universe, max int, all bool, allowSpells bool) []Upgrade {
 
 	// Aggregate each coster into a unique slice of costers.
 	costers := []Coster{}
@@ -386,12 +762,13 @@ func (c *Character) Suggest(universe Universe, max int, all bool, allowSpells bo
 	// The slice of appliable upgrades.
 	var appliable []Upgrade
 
-	// Attempt to apply each coster once.
+	// Attempt to apply each coster once, on a copy of the character.
+	clone := c.clone()
 	for _, coster := range costers {
 		var upgrade Upgrade
 
 		// Don't propose the upgrade its cost cannot be defined
-		cost, err := coster.Cost(universe, *c)
+		cost, err := coster.Cost(universe, clone)
 		if err != nil {
 			continue
 		}
@@ -410,7 +787,7 @@ func (c *Character) Suggest(universe Universe, max int, all bool, allowSpells bo
 		upgrade.Mark = MarkApply
 		upgrade.Name = coster.DefaultName()
 
-		err = coster.Apply(c, upgrade)
+		err = coster.Apply(&clone, upgrade)
 		if err != nil {
 			continue
 		}
@@ -427,6 +804,13 @@ func (c *Character) Suggest(universe Universe, max int, all bool, allowSpells bo
 		return ci < cj
 	})
 
+	return appliable
+}
+
+// Suggest prints the next purchasable upgrades of the character.
+func (c *Character) Suggest(universe Universe, max int, all bool, allowSpells bool) {
+	appliable := c.Suggestions(universe, max, all, allowSpells)
+
 	// Print the name.
 	fmt.Printf("%s\t%s\n", theme.Title("Name"), c.Name)
 
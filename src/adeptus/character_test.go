@@ -0,0 +1,167 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_NewCharacter_Balances(t *testing.T) {
+	universe := Universe{
+		Characteristics: []Characteristic{
+			{Name: "WS"},
+		},
+		Skills: []Skill{
+			{Name: "Awareness"},
+		},
+		Costs: CostMatrix{
+			"skill": {0: {1: 200, 2: 400}},
+		},
+	}
+
+	sheet := Sheet{
+		Characteristics: Characteristics{
+			{Mark: MarkSpecial, Name: "WS 30", Cost: IntP(0), Line: 3},
+		},
+		Sessions: []Session{
+			{
+				Reward: IntP(300),
+				Upgrades: []Upgrade{
+					{Mark: MarkApply, Name: "Awareness", Line: 6},
+				},
+				Line: 5,
+			},
+			{
+				Reward: IntP(100),
+				Upgrades: []Upgrade{
+					{Mark: MarkApply, Name: "Awareness", Line: 9},
+					{Mark: MarkApply, Name: "Rule: Debt", Cost: IntP(100), Line: 10},
+				},
+				Line: 8,
+			},
+		},
+	}
+
+	cases := []struct {
+		options  Options
+		balances []Balance
+		warnings int
+		err      bool
+		code     ErrorCode
+	}{
+		{
+			options: Options{},
+			err:     true,
+			code:    OverspentExperience,
+		},
+		{
+			options: Options{AllowDebt: true},
+			balances: []Balance{
+				{
+					Session:   sheet.Sessions[0],
+					Earned:    300,
+					Spent:     200,
+					Remaining: 100,
+					Upgrades: []Record{
+						{Upgrade: Upgrade{Mark: MarkApply, Name: "Awareness", Cost: IntP(200), Line: 6}, Type: "skill", Tier: 1, Computed: IntP(200)},
+					},
+				},
+				{
+					Session:   sheet.Sessions[1],
+					Earned:    100,
+					Spent:     500,
+					Remaining: -300,
+					Upgrades: []Record{
+						{Upgrade: Upgrade{Mark: MarkApply, Name: "Awareness", Cost: IntP(400), Line: 9}, Type: "skill", Tier: 2, Computed: IntP(400)},
+						{Upgrade: Upgrade{Mark: MarkApply, Name: "Rule: Debt", Cost: IntP(100), Line: 10}, Type: "rule", Explicit: true, Computed: IntP(0)},
+					},
+				},
+			},
+			warnings: 1,
+			err:      false,
+		},
+	}
+
+	for i, c := range cases {
+		out, err := NewCharacter(universe, sheet, c.options)
+		if (err != nil) != c.err {
+			if c.err {
+				t.Logf("Expected error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+		} else if err != nil {
+			code := err.(Error).Code
+			if c.code != code {
+				t.Logf("Unexpected error on case %d:", i+1)
+				t.Logf("	Expected %s", NewError(c.code))
+				t.Logf("	Having %s", err)
+				t.Fail()
+			}
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if len(out.Warnings) != c.warnings {
+			t.Logf("Unexpected warnings on case %d: %v", i+1, out.Warnings)
+			t.Fail()
+		}
+
+		if !reflect.DeepEqual(out.Balances, c.balances) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.balances)
+			t.Logf("	Having %v", out.Balances)
+			t.Fail()
+		}
+	}
+}
+
+func Test_NewCharacter_MissingCharacteristics(t *testing.T) {
+	universe := Universe{
+		Characteristics: []Characteristic{
+			{Name: "WS"},
+			{Name: "BS"},
+			{Name: "FEL"},
+		},
+	}
+
+	sheet := Sheet{
+		Characteristics: Characteristics{
+			{Mark: MarkSpecial, Name: "BS 30", Cost: IntP(0), Line: 3},
+		},
+	}
+
+	_, err := NewCharacter(universe, sheet, Options{})
+	if err == nil {
+		t.Logf("Expected error")
+		t.FailNow()
+	}
+
+	expected := NewError(MissingCharacteristics, Position{Line: 3}, "WS, FEL")
+	if !reflect.DeepEqual(err, expected) {
+		t.Logf("Unexpected error:")
+		t.Logf("	Expected %s", expected)
+		t.Logf("	Having %s", err)
+		t.Fail()
+	}
+}
+
+func Test_Characteristic_Cost_Unowned(t *testing.T) {
+	universe := Universe{
+		Characteristics: []Characteristic{
+			{Name: "WS"},
+		},
+	}
+
+	character := Character{
+		Characteristics: map[string]Characteristic{},
+	}
+
+	_, err := universe.Characteristics[0].Cost(universe, character)
+	if err == nil || err.(Error).Code != UnownedCharacteristic {
+		t.Logf("Unexpected error: %v", err)
+		t.Fail()
+	}
+}
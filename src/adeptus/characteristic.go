@@ -8,23 +8,32 @@ import (
 
 // Characteristic is is a character's trait which holds a value.
 type Characteristic struct {
-	Name      string     `yaml:"name"`
-	Aptitudes []Aptitude `yaml:"aptitudes"`
-	Tier      int        `yaml:"tier"`
-	Value     int        `yaml:"-"`
+	Name       string     `yaml:"name"`
+	Aptitudes  []Aptitude `yaml:"aptitudes"`
+	Tier       int        `yaml:"tier"`
+	Alignments []string   `yaml:"alignments"`
+	Value      int        `yaml:"-"`
+
+	// Advance is the part of the value given by the purchased advances.
+	Advance int `yaml:"-"`
 }
 
 // Cost returns the cost of a standard characteristic upgrade given the character's aptitudes and the characteristic current tier.
 func (c Characteristic) Cost(universe Universe, character Character) (int, error) {
 
-	// If the characteristic isn't defined, the cost is always 0. This happens
-	// (presumably) only on the header upgrades.
+	// The characteristic block defines each characteristic, so a missing one
+	// can't be upgraded.
 	if _, found := character.Characteristics[c.Name]; !found {
-		return 0, nil
+		return 0, NewError(UnownedCharacteristic, c.Name)
 	}
 
-	// Return the price as determined by the cost matrix.
-	return universe.Costs.Price("characteristic", character.Intersect(c.Aptitudes), character.Characteristics[c.Name].Tier+1)
+	// The characteristics are advances of the character's career, if any.
+	if career, found := universe.FindCareer(character.Career); found {
+		return career.Cost(universe, character, "characteristic", c.Name, character.Characteristics[c.Name].Tier)
+	}
+
+	// Return the price as determined by the cost model of the universe.
+	return universe.Price("characteristic", universe.Matches(character, c.Aptitudes, c.Alignments), character.Characteristics[c.Name].Tier+1, character)
 }
 
 // Level returns a string representing the tier of the characteristic.
@@ -60,24 +69,27 @@ func (c Characteristic) Apply(character *Character, upgrade Upgrade) error {
 
 	// Check the tier is not negative.
 	if c.Tier < 0 {
-		return NewError(ForbidenUpgradeLoss, upgrade.Line, c.Name)
+		return NewError(ForbidenUpgradeLoss, upgrade.Position(), c.Name)
 	}
 
 	// Parse the characteristic's upgrade value.
 	raw := strings.TrimSpace(strings.TrimLeft(upgrade.Name, c.Name))
 	value, err := strconv.Atoi(raw)
 	if err != nil {
-		return NewError(InvalidUpgradeValue, upgrade.Line)
+		return NewError(InvalidUpgradeValue, upgrade.Position())
 	}
 
 	// Deny absolute values for non special marks.
 	if upgrade.Mark != MarkSpecial && !strings.HasPrefix(raw, "+") {
-		return NewError(ForbidenUpgradeValue, upgrade.Line)
+		return NewError(ForbidenUpgradeValue, upgrade.Position())
 	}
 
 	// Update the characteristic's value.
 	if strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-") {
 		c.Value += value
+		if upgrade.Mark != MarkSpecial {
+			c.Advance += value
+		}
 	} else {
 		c.Value = value
 	}
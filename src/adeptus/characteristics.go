@@ -23,18 +23,18 @@ func parseCharacteristics(block []line) (Characteristics, error) {
 		// The line should be made of label and value
 		splits := strings.Fields(line.Text)
 		if len(splits) != 2 {
-			return Characteristics{}, NewError(InvalidUpgradeFormat, line.Number)
+			return Characteristics{}, NewError(InvalidUpgradeFormat, line.Position())
 		}
 
 		// Check the value is numeric
 		_, err := strconv.Atoi(splits[1])
 		if err != nil {
-			return Characteristics{}, NewError(InvalidUpgradeValue, line.Number)
+			return Characteristics{}, NewError(InvalidUpgradeValue, line.Locate(splits[1]))
 		}
 
 		// Check the value is absolute
 		if strings.ContainsAny(splits[1], "+|-") {
-			return Characteristics{}, NewError(ForbidenUpgradeValue, line.Number)
+			return Characteristics{}, NewError(ForbidenUpgradeValue, line.Locate(splits[1]))
 		}
 
 		u := Upgrade{
@@ -42,6 +42,7 @@ func parseCharacteristics(block []line) (Characteristics, error) {
 			Name: strings.Join(splits, " "),
 			Cost: IntP(0),
 			Line: line.Number,
+			File: line.File,
 		}
 
 		// Add the characteristic to the list
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/slice"
+)
+
+// ConditionPrefix is the prefix of the conditions upgrades.
+const ConditionPrefix = "condition"
+
+// Condition is a temporary state of the character, like a wound, fatigue or
+// a blessing, modifying its traits until it expires or is removed.
+type Condition struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Modifiers   []Modifier `yaml:"modifiers"`
+
+	// expiry is the raw expiry of the upgrade, if any.
+	expiry string
+}
+
+// Effect is a condition affecting the character, along with its expiry.
+type Effect struct {
+	Condition Condition
+
+	// Until is the last date of the sessions the condition lasts, if any.
+	Until time.Time
+
+	// Expiry is the number of the session the condition expires at, if any.
+	Expiry int
+}
+
+// String returns the expiry of the effect.
+func (e Effect) String() string {
+	switch {
+	case !e.Until.IsZero():
+		return fmt.Sprintf("until %s", e.Until.Format("2006/01/02"))
+	case e.Expiry != 0:
+		return fmt.Sprintf("until session %d", e.Expiry)
+	}
+	return "until removed"
+}
+
+// Expired returns true if the effect is expired at the given session.
+func (e Effect) Expired(number int, date time.Time) bool {
+	if !e.Until.IsZero() && date.After(e.Until) {
+		return true
+	}
+	return e.Expiry != 0 && number >= e.Expiry
+}
+
+// Cost returns 0, a condition has no cost.
+func (c Condition) Cost(u Universe, character Character) (int, error) {
+	return 0, nil
+}
+
+// Apply applys the upgrade on the character:
+// * gives the condition to the character, renewing its expiry if it is
+// already affected, or removes it
+// * does not affect the character's XP
+func (c Condition) Apply(character *Character, upgrade Upgrade) error {
+
+	switch upgrade.Mark {
+	case MarkSpecial:
+		return NewError(ForbidenUpgradeMark, upgrade.Position())
+	case MarkRevert:
+		_, found := character.Conditions[c.Name]
+		if !found {
+			return NewError(ForbidenUpgradeLoss, upgrade.Position())
+		}
+		delete(character.Conditions, c.Name)
+		return nil
+	}
+
+	effect := Effect{}
+
+	// The expiry is either a number of sessions, including the current one,
+	// or the date of the last session.
+	fields := strings.Fields(c.expiry)
+	switch {
+	case len(fields) == 0:
+	case len(fields) == 3 && strings.EqualFold(fields[0], "for") && strings.HasPrefix(strings.ToLower(fields[2]), "session"):
+		sessions, err := strconv.Atoi(fields[1])
+		if err != nil || sessions <= 0 {
+			return NewError(InvalidConditionExpiry, upgrade.Position(), c.expiry)
+		}
+		effect.Expiry = character.session + sessions
+	case len(fields) == 2 && strings.EqualFold(fields[0], "until"):
+		until, err := parseDate(fields[1])
+		if err != nil {
+			return NewError(InvalidConditionExpiry, upgrade.Position(), c.expiry)
+		}
+		effect.Until = until
+	default:
+		return NewError(InvalidConditionExpiry, upgrade.Position(), c.expiry)
+	}
+
+	c.expiry = ""
+	effect.Condition = c
+	character.Conditions[c.Name] = effect
+
+	return nil
+}
+
+// DefaultName returns the default upgrade name.
+func (c Condition) DefaultName() string {
+	return fmt.Sprintf("%s: %s", strings.Title(ConditionPrefix), c.Name)
+}
+
+// FindCondition returns the condition corresponding to the given label or a
+// zero value, and a boolean indicating if it was found.
+func (u Universe) FindCondition(upgrade Upgrade) (Condition, bool) {
+
+	// Conditions upgrades are defined by the condition prefix, followed by
+	// the name of the condition and eventually its expiry.
+	// Examples: Condition: Fatigued for 2 sessions
+	fields := strings.SplitN(upgrade.Name, ":", 2)
+	if len(fields) != 2 || !strings.EqualFold(strings.TrimSpace(fields[0]), ConditionPrefix) {
+		return Condition{}, false
+	}
+	label := strings.TrimSpace(fields[1])
+
+	// Keep the longest name, the expiry following it.
+	var best Condition
+	found := false
+	for _, condition := range u.Conditions {
+		if len(label) < len(condition.Name) || !strings.EqualFold(label[:len(condition.Name)], condition.Name) {
+			continue
+		}
+
+		rest := label[len(condition.Name):]
+		if len(rest) != 0 && !strings.HasPrefix(rest, " ") {
+			continue
+		}
+
+		if found && len(best.Name) >= len(condition.Name) {
+			continue
+		}
+
+		condition.expiry = strings.TrimSpace(rest)
+		best, found = condition, true
+	}
+
+	return best, found
+}
+
+// expire removes the conditions expired at the session, recording their
+// removal in the history.
+func (c *Character) expire(session Session) {
+	expired := []string{}
+	for name, effect := range c.Conditions {
+		if effect.Expired(c.session, session.Date) {
+			expired = append(expired, name)
+		}
+	}
+	slice.Sort(expired, func(i, j int) bool {
+		return expired[i] < expired[j]
+	})
+
+	for _, name := range expired {
+		effect := c.Conditions[name]
+		delete(c.Conditions, name)
+
+		upgrade := Upgrade{
+			Mark: MarkRevert,
+			Name: effect.Condition.DefaultName(),
+			Cost: IntP(0),
+			Line: session.Line,
+			File: session.File,
+		}
+		record := NewRecord(upgrade, effect.Condition, *c)
+		record.Computed = IntP(0)
+		record.Expired = true
+		c.History = append(c.History, record)
+	}
+}
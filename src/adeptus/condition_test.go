@@ -0,0 +1,149 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Universe_FindCondition(t *testing.T) {
+	universe := Universe{
+		Conditions: []Condition{
+			{Name: "Fatigued"},
+			{Name: "Fatigued Badly"},
+		},
+	}
+
+	cases := []struct {
+		in     string
+		name   string
+		expiry string
+		found  bool
+	}{
+		{in: "Condition: Fatigued", name: "Fatigued", found: true},
+		{in: "condition: fatigued for 2 sessions", name: "Fatigued", expiry: "for 2 sessions", found: true},
+		{in: "Condition: Fatigued until 2020/02/01", name: "Fatigued", expiry: "until 2020/02/01", found: true},
+		{in: "Condition: Fatigued Badly", name: "Fatigued Badly", found: true},
+		{in: "Condition: Fatiguedly", found: false},
+		{in: "Fatigued", found: false},
+		{in: "Rule: Fatigued", found: false},
+	}
+
+	for i, c := range cases {
+		out, found := universe.FindCondition(Upgrade{Name: c.in})
+		if found != c.found || out.Name != c.name || out.expiry != c.expiry {
+			t.Logf("Unexpected result on case %d: expected %s (%s), having %s (%s)", i+1, c.name, c.expiry, out.Name, out.expiry)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Condition_Apply(t *testing.T) {
+	cases := []struct {
+		expiry string
+		effect Effect
+		err    bool
+	}{
+		{expiry: "", effect: Effect{}},
+		{expiry: "for 2 sessions", effect: Effect{Expiry: 5}},
+		{expiry: "for 1 session", effect: Effect{Expiry: 4}},
+		{expiry: "until 2020/02/01", effect: Effect{Until: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		{expiry: "for 0 sessions", err: true},
+		{expiry: "until tomorrow", err: true},
+		{expiry: "forever", err: true},
+	}
+
+	for i, c := range cases {
+		character := Character{Conditions: map[string]Effect{}, session: 3}
+		condition := Condition{Name: "Fatigued", expiry: c.expiry}
+
+		err := condition.Apply(&character, Upgrade{Mark: MarkApply, Name: "Condition: Fatigued " + c.expiry})
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+			continue
+		}
+		if c.err {
+			continue
+		}
+
+		out := character.Conditions["Fatigued"]
+		if out.Expiry != c.effect.Expiry || !out.Until.Equal(c.effect.Until) {
+			t.Logf("Unexpected effect on case %d: expected %v, having %v", i+1, c.effect, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_NewCharacter_Conditions(t *testing.T) {
+	universe := Universe{
+		Characteristics: []Characteristic{
+			{Name: "STR"},
+		},
+		Conditions: []Condition{
+			{Name: "Fatigued", Modifiers: []Modifier{{Target: "STR", Value: -10}}},
+			{Name: "Blessed", Modifiers: []Modifier{{Target: "STR", Value: 5}}},
+		},
+	}
+
+	date := func(day int) time.Time {
+		return time.Date(2020, 1, day, 0, 0, 0, 0, time.UTC)
+	}
+	session := func(day int, upgrades ...string) Session {
+		s := Session{Date: date(day), Reward: IntP(0), Line: day}
+		for _, name := range upgrades {
+			s.Upgrades = append(s.Upgrades, Upgrade{Mark: MarkApply, Name: name, Line: day})
+		}
+		return s
+	}
+
+	cases := []struct {
+		sessions []Session
+		modifier int
+		expired  int
+	}{
+		{
+			sessions: []Session{session(1, "Condition: Fatigued")},
+			modifier: -10,
+		},
+		{
+			sessions: []Session{session(1, "Condition: Fatigued for 2 sessions"), session(2)},
+			modifier: -10,
+		},
+		{
+			sessions: []Session{session(1, "Condition: Fatigued for 2 sessions"), session(2), session(3)},
+			modifier: 0,
+			expired:  1,
+		},
+		{
+			sessions: []Session{session(1, "Condition: Fatigued for 1 session", "Condition: Blessed until 2020/01/02"), session(2)},
+			modifier: 5,
+			expired:  1,
+		},
+		{
+			sessions: []Session{session(1, "Condition: Blessed until 2020/01/02"), session(2), session(3)},
+			modifier: 0,
+			expired:  1,
+		},
+	}
+
+	for i, c := range cases {
+		character, err := NewCharacter(universe, Sheet{Characteristics: Characteristics{{Mark: MarkSpecial, Name: "STR 30", Cost: IntP(0)}}, Sessions: c.sessions}, Options{})
+		if err != nil {
+			t.Logf("Unexpected error on case %d: %s", i+1, err)
+			t.Fail()
+			continue
+		}
+
+		expired := 0
+		for _, record := range character.History {
+			if record.Expired {
+				expired++
+			}
+		}
+
+		if character.Modifier("str") != c.modifier || expired != c.expired {
+			t.Logf("Unexpected result on case %d: modifier %d with %d expired", i+1, character.Modifier("str"), expired)
+			t.Fail()
+		}
+	}
+}
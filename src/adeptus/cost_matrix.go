@@ -38,6 +38,19 @@ func (c CostMatrix) Price(typ string, matches int, tier int) (int, error) {
 	return cost, nil
 }
 
+// Tiers returns the highest tier priced for the given type.
+func (c CostMatrix) Tiers(typ string) int {
+	max := 0
+	for _, tiers := range c[typ] {
+		for tier := range tiers {
+			if tier > max {
+				max = tier
+			}
+		}
+	}
+	return max
+}
+
 // MarshalYAML return the YAML representation of the cost matrix.
 // Implements the Marshaller interface.
 func (c *CostMatrix) MarshalYAML() ([]byte, error) {
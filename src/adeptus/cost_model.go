@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Pricing holds what the price of an upgrade depends on.
+type Pricing struct {
+	Type      string
+	Matches   int
+	Tier      int
+	Character Character
+}
+
+// CostModel is the interface implemented by the ways of pricing the
+// upgrades of a type.
+type CostModel interface {
+	Price(Pricing) (int, error)
+
+	// Tiers returns the highest tier priced for the given type, or 0 if the
+	// tiers aren't bounded.
+	Tiers(typ string) int
+}
+
+// LinearCost is a cost model where the cost is an affine function of the
+// tier and of the number of matching aptitudes.
+type LinearCost struct {
+	Base    int `yaml:"base"`
+	Tier    int `yaml:"tier"`
+	Matches int `yaml:"matches"`
+}
+
+// Price returns the cost corresponding to the pricing. Implements CostModel.
+func (l LinearCost) Price(pricing Pricing) (int, error) {
+	return l.Base + l.Tier*pricing.Tier + l.Matches*pricing.Matches, nil
+}
+
+// Tiers returns 0, a linear cost pricing every tier. Implements CostModel.
+func (l LinearCost) Tiers(typ string) int {
+	return 0
+}
+
+// CostRule is the cost model declared by the universe for a type of
+// upgrade. Exactly one of the table, the linear function or the formula is
+// defined.
+type CostRule struct {
+	Table   map[int]map[int]int `yaml:"table"`
+	Linear  *LinearCost         `yaml:"linear"`
+	Formula string              `yaml:"formula"`
+
+	// MaxTier is the highest tier of the type, the highest tier of the table
+	// by default.
+	MaxTier int `yaml:"tiers"`
+
+	expression expression
+}
+
+// Compile checks the rule is well defined and compiles its formula.
+func (r *CostRule) Compile() error {
+	count := 0
+	if r.Table != nil {
+		count++
+	}
+	if r.Linear != nil {
+		count++
+	}
+	if len(r.Formula) != 0 {
+		count++
+	}
+	if count != 1 {
+		return fmt.Errorf("exactly one of table, linear or formula must be defined")
+	}
+	if r.MaxTier < 0 {
+		return fmt.Errorf("the tiers can't be negative")
+	}
+
+	if len(r.Formula) == 0 {
+		return nil
+	}
+
+	e, err := compileFormula(r.Formula)
+	if err != nil {
+		return err
+	}
+	r.expression = e
+	return nil
+}
+
+// Price returns the cost corresponding to the pricing. Implements CostModel.
+func (r CostRule) Price(pricing Pricing) (int, error) {
+	switch {
+	case r.Table != nil:
+		return CostMatrix{pricing.Type: r.Table}.Price(pricing.Type, pricing.Matches, pricing.Tier)
+	case r.Linear != nil:
+		return r.Linear.Price(pricing)
+	case r.expression != nil:
+		cost, err := r.expression(pricing)
+		if err != nil {
+			return 0, NewError(InvalidCostFormula, pricing.Type, err)
+		}
+		return cost, nil
+	}
+
+	return 0, NewError(UndefinedTypeCost, pricing.Type)
+}
+
+// Tiers returns the highest tier of the type, declared by the rule or priced
+// by its table. Implements CostModel.
+func (r CostRule) Tiers(typ string) int {
+	if r.MaxTier != 0 || r.Table == nil {
+		return r.MaxTier
+	}
+	return CostMatrix{typ: r.Table}.Tiers(typ)
+}
+
+// CostModel returns the cost model of the given type of upgrade: the cost
+// rule declared by the universe, or the cost matrix by default.
+func (u Universe) CostModel(typ string) CostModel {
+	if rule, found := u.CostRules[typ]; found {
+		return rule
+	}
+	return matrixModel{u.Costs}
+}
+
+// Price returns the cost of an upgrade of the given type for the character,
+// given its number of matching aptitudes and the tier it reaches.
+func (u Universe) Price(typ string, matches int, tier int, character Character) (int, error) {
+	return u.CostModel(typ).Price(Pricing{
+		Type:      typ,
+		Matches:   matches,
+		Tier:      tier,
+		Character: character,
+	})
+}
+
+// matrixModel is the default cost model, pricing from the cost matrix.
+type matrixModel struct {
+	CostMatrix
+}
+
+// Price returns the cost in the matrix corresponding to the pricing.
+// Implements CostModel.
+func (m matrixModel) Price(pricing Pricing) (int, error) {
+	return m.CostMatrix.Price(pricing.Type, pricing.Matches, pricing.Tier)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Universe_Price(t *testing.T) {
+	universe, err := ParseUniverse(strings.NewReader(`
+costs:
+  skill:
+    0: {1: 300, 2: 600}
+  talent:
+    0: {1: 600}
+cost_rules:
+  talent:
+    table:
+      0: {1: 500}
+  characteristic:
+    linear: {base: 100, tier: 250, matches: -50}
+  spell:
+    formula: "tier * 100 + gauge(\"Corruption\")"
+`))
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	character := Character{
+		Gauges: map[string]Gauge{
+			"Corruption": {Name: "Corruption", Value: 7},
+		},
+	}
+
+	cases := []struct {
+		typ     string
+		matches int
+		tier    int
+		out     int
+		err     bool
+	}{
+		{typ: "skill", matches: 0, tier: 2, out: 600},
+		{typ: "talent", matches: 0, tier: 1, out: 500},
+		{typ: "talent", matches: 1, tier: 1, err: true},
+		{typ: "characteristic", matches: 1, tier: 2, out: 550},
+		{typ: "spell", matches: 0, tier: 3, out: 307},
+		{typ: "gauge", matches: 0, tier: 1, err: true},
+	}
+
+	for i, c := range cases {
+		out, err := universe.Price(c.typ, c.matches, c.tier, character)
+		if (err != nil) != c.err {
+			if c.err {
+				t.Logf("Expected error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+			continue
+		}
+
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %d", c.out)
+			t.Logf("	Having %d", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_ParseUniverse_CostRules(t *testing.T) {
+	cases := []string{
+		`cost_rules: {skill: {}}`,
+		`cost_rules: {skill: {formula: "tier *", linear: {base: 100}}}`,
+		`cost_rules: {skill: {formula: "tier *"}}`,
+	}
+
+	for i, c := range cases {
+		_, err := ParseUniverse(strings.NewReader(c))
+		if err == nil {
+			t.Logf("Expected error on case %d", i+1)
+			t.Fail()
+			continue
+		}
+
+		if err.(Error).Code != InvalidCostRule {
+			t.Logf("Unexpected error on case %d: %s", i+1, err)
+			t.Fail()
+		}
+	}
+}
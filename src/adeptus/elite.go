@@ -0,0 +1,282 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Elite is an advance purchased with the approval of the game master, which
+// grants several upgrades at once, like the elite advances.
+type Elite struct {
+	Name         string   `yaml:"name"`
+	Description  string   `yaml:"description"`
+	XP           int      `yaml:"xp"`
+	Requirements []string `yaml:"requirements"`
+	Upgrades     []string `yaml:"upgrades"`
+}
+
+// Cost returns the experience cost of the elite advance.
+func (e Elite) Cost(u Universe, character Character) (int, error) {
+	return e.XP, nil
+}
+
+// Apply applys the upgrade on the character:
+// * gives or removes the elite advance
+// * does not grant its upgrades, see Grant
+// * does not affect the character's XP
+func (e Elite) Apply(character *Character, upgrade Upgrade) error {
+
+	_, found := character.Elites[e.Name]
+
+	switch upgrade.Mark {
+	case MarkSpecial:
+		return NewError(ForbidenUpgradeMark, upgrade.Position())
+	case MarkApply:
+		if found {
+			return NewError(DuplicateUpgrade, upgrade.Position())
+		}
+		character.Elites[e.Name] = e
+	case MarkRevert:
+		if !found {
+			return NewError(ForbidenUpgradeLoss, upgrade.Position())
+		}
+		delete(character.Elites, e.Name)
+	}
+
+	return nil
+}
+
+// DefaultName returns the default upgrade name.
+func (e Elite) DefaultName() string {
+	return e.Name
+}
+
+// Grant applies the upgrades granted by the elite advance on the character,
+// or reverts them in reverse order when the advance is reverted. The granted
+// upgrades are free, and recorded in the history as granted by the advance.
+func (e Elite) Grant(character *Character, upgrade Upgrade, universe Universe) error {
+
+	// Check the requirements before granting anything.
+	if upgrade.Mark == MarkApply {
+		for _, requirement := range e.Requirements {
+			if !character.Meets(requirement, universe) {
+				return NewError(UnmetRequirement, upgrade.Position(), requirement, e.Name)
+			}
+		}
+	}
+
+	// The granted upgrades aren't purchases, so reverting them refunds
+	// nothing: the refund is the one of the advance.
+	refund := character.options.Refund
+	character.options.Refund = false
+	defer func() {
+		character.options.Refund = refund
+	}()
+
+	grants := make([]string, len(e.Upgrades))
+	copy(grants, e.Upgrades)
+	if upgrade.Mark == MarkRevert {
+		for i, j := 0, len(grants)-1; i < j; i, j = i+1, j-1 {
+			grants[i], grants[j] = grants[j], grants[i]
+		}
+	}
+
+	for _, raw := range grants {
+		grant := Upgrade{
+			Mark: upgrade.Mark,
+			Name: raw,
+			Cost: IntP(0),
+			Line: upgrade.Line,
+			File: upgrade.File,
+		}
+
+		// The characteristics and gauges are changed by a value, which is
+		// subtracted when reverted.
+		coster, found := universe.FindCoster(grant)
+		switch coster.(type) {
+		case Characteristic, Gauge:
+			grant.Mark = MarkSpecial
+			if upgrade.Mark == MarkRevert {
+				grant.Name = negate(raw)
+			}
+		}
+		if !found && !character.options.Lenient {
+			return NewError(UndefinedUpgrade, upgrade.Position(), raw, suggestions(universe.Suggest(grant)))
+		}
+		if !found {
+			coster = Rule{Name: raw}
+		}
+
+		// The aptitudes and rules already owned are left as is, so that
+		// reverting the advance only takes back those it gave.
+		switch coster.(type) {
+		case Aptitude, Rule:
+			if upgrade.Mark == MarkApply && character.owns(coster) {
+				continue
+			}
+			if upgrade.Mark == MarkRevert && !character.granted(e.Name, raw) {
+				continue
+			}
+		}
+
+		index := len(character.History)
+		err := character.ApplyUpgrade(grant, universe)
+		if err != nil {
+			return err
+		}
+		character.History[index].Elite = e.Name
+	}
+
+	return nil
+}
+
+// owns returns true if the character has the aptitude or the rule.
+func (c *Character) owns(coster Coster) bool {
+	found := false
+	switch coster := coster.(type) {
+	case Aptitude:
+		_, found = c.Aptitudes[string(coster)]
+	case Rule:
+		_, found = c.Rules[coster.Name]
+	}
+	return found
+}
+
+// granted returns true if the last grant of the upgrade by the elite advance
+// gave it to the character.
+func (c *Character) granted(elite string, name string) bool {
+	for i := len(c.History) - 1; i >= 0; i-- {
+		record := c.History[i]
+		if record.Elite == elite && record.Name == name {
+			return record.Mark == MarkApply
+		}
+	}
+	return false
+}
+
+// negate returns the upgrade name with the sign of its value inverted.
+func negate(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return name
+	}
+
+	value := fields[len(fields)-1]
+	switch {
+	case strings.HasPrefix(value, "+"):
+		value = "-" + value[1:]
+	case strings.HasPrefix(value, "-"):
+		value = "+" + value[1:]
+	}
+	fields[len(fields)-1] = value
+
+	return strings.Join(fields, " ")
+}
+
+// Meets returns true if the character meets the requirement: the name of a
+// trait, background or elite advance the character has, or the name of a
+// characteristic or gauge followed by its minimum value.
+func (c *Character) Meets(requirement string, universe Universe) bool {
+
+	// Look for a minimum value.
+	fields := strings.Fields(requirement)
+	if len(fields) > 1 {
+		if min, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			name := strings.Join(fields[:len(fields)-1], " ")
+			for _, characteristic := range c.Characteristics {
+				if strings.EqualFold(characteristic.Name, name) {
+					return characteristic.Value >= min
+				}
+			}
+			for _, gauge := range c.Gauges {
+				if strings.EqualFold(gauge.Name, name) {
+					return gauge.Value >= min
+				}
+			}
+			return false
+		}
+	}
+
+	coster, found := universe.FindCoster(Upgrade{Name: requirement})
+	if found {
+		switch coster := coster.(type) {
+		case Skill:
+			_, found = c.Skills[coster.FullName()]
+		case Talent:
+			_, found = c.Talents[coster.FullName()]
+		case Aptitude:
+			_, found = c.Aptitudes[string(coster)]
+		case Rule:
+			_, found = c.Rules[coster.Name]
+		case Spell:
+			_, found = c.Spells[coster.Name]
+		case Elite:
+			_, found = c.Elites[coster.Name]
+		default:
+			found = false
+		}
+		return found
+	}
+
+	for _, background := range c.Backgrounds {
+		if strings.EqualFold(background.Name, requirement) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clone returns a copy of the character which can be changed without
+// affecting the character.
+func (c Character) clone() Character {
+	clone := c
+
+	clone.Backgrounds = make(map[string]Background)
+	for k, v := range c.Backgrounds {
+		clone.Backgrounds[k] = v
+	}
+	clone.Aptitudes = make(map[string]Aptitude)
+	for k, v := range c.Aptitudes {
+		clone.Aptitudes[k] = v
+	}
+	clone.Characteristics = make(map[string]Characteristic)
+	for k, v := range c.Characteristics {
+		clone.Characteristics[k] = v
+	}
+	clone.Skills = make(map[string]Skill)
+	for k, v := range c.Skills {
+		clone.Skills[k] = v
+	}
+	clone.Talents = make(map[string]Talent)
+	for k, v := range c.Talents {
+		clone.Talents[k] = v
+	}
+	clone.Gauges = make(map[string]Gauge)
+	for k, v := range c.Gauges {
+		clone.Gauges[k] = v
+	}
+	clone.Rules = make(map[string]Rule)
+	for k, v := range c.Rules {
+		clone.Rules[k] = v
+	}
+	clone.Spells = make(map[string]Spell)
+	for k, v := range c.Spells {
+		clone.Spells[k] = v
+	}
+	clone.Elites = make(map[string]Elite)
+	for k, v := range c.Elites {
+		clone.Elites[k] = v
+	}
+	clone.Conditions = make(map[string]Effect)
+	for k, v := range c.Conditions {
+		clone.Conditions[k] = v
+	}
+	clone.History = append([]Record{}, c.History...)
+	clone.Balances = append([]Balance{}, c.Balances...)
+	clone.Crossings = append([]Crossing{}, c.Crossings...)
+	clone.pending = append([]Crossing{}, c.pending...)
+	clone.Warnings = append([]error{}, c.Warnings...)
+
+	return clone
+}
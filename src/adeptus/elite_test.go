@@ -0,0 +1,150 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Character_ApplyUpgrade_Elite(t *testing.T) {
+	universe := Universe{
+		Aptitudes: []Aptitude{"Psyker"},
+		Characteristics: []Characteristic{
+			{Name: "WP"},
+		},
+		Gauges: []Gauge{
+			{Name: "Psy Rating"},
+		},
+		Talents: []Talent{
+			{Name: "Psyniscience"},
+		},
+		Elites: []Elite{
+			{
+				Name:         "Sanctioned Psyker",
+				XP:           300,
+				Requirements: []string{"WP 35"},
+				Upgrades: []string{
+					"Psyker",
+					"Psy Rating +1",
+					"Psyniscience",
+					"Rule: Sanctioned",
+					"WP +5",
+				},
+			},
+		},
+	}
+
+	newCharacter := func(willpower int, talents ...string) *Character {
+		c := &Character{
+			Backgrounds:     map[string]Background{},
+			Aptitudes:       map[string]Aptitude{},
+			Characteristics: map[string]Characteristic{"WP": {Name: "WP", Value: willpower}},
+			Skills:          map[string]Skill{},
+			Talents:         map[string]Talent{},
+			Gauges:          map[string]Gauge{},
+			Rules:           map[string]Rule{},
+			Spells:          map[string]Spell{},
+			Elites:          map[string]Elite{},
+		}
+		for _, talent := range talents {
+			c.Talents[talent] = Talent{Name: talent, Value: 1}
+		}
+		return c
+	}
+
+	// The elite advance grants each of its upgrades.
+	c := newCharacter(35)
+	err := c.ApplyUpgrade(Upgrade{Mark: MarkApply, Name: "sanctioned psyker", Line: 3}, universe)
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	_, aptitude := c.Aptitudes["Psyker"]
+	_, talent := c.Talents["Psyniscience"]
+	_, rule := c.Rules["Sanctioned"]
+	_, elite := c.Elites["Sanctioned Psyker"]
+	if !aptitude || !talent || !rule || !elite || c.Gauges["Psy Rating"].Value != 1 || c.Characteristics["WP"].Value != 40 {
+		t.Logf("Unexpected character: %v", c)
+		t.Fail()
+	}
+	if c.Spent != 300 || len(c.History) != 6 || c.History[5].Elite != "Sanctioned Psyker" || c.History[0].Type != "elite" {
+		t.Logf("Unexpected history: %d spent with %v", c.Spent, c.History)
+		t.Fail()
+	}
+
+	// Reverting the elite advance reverts each of its upgrades.
+	err = c.ApplyUpgrade(Upgrade{Mark: MarkRevert, Name: "Sanctioned Psyker", Cost: IntP(0), Line: 4}, universe)
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	expected := newCharacter(35)
+	expected.Gauges["Psy Rating"] = Gauge{Name: "Psy Rating"}
+	if !reflect.DeepEqual(c.Aptitudes, expected.Aptitudes) || !reflect.DeepEqual(c.Talents, expected.Talents) ||
+		!reflect.DeepEqual(c.Rules, expected.Rules) || !reflect.DeepEqual(c.Elites, expected.Elites) ||
+		!reflect.DeepEqual(c.Gauges, expected.Gauges) || !reflect.DeepEqual(c.Characteristics, expected.Characteristics) {
+		t.Logf("Unexpected reverted character: %v", c)
+		t.Fail()
+	}
+
+	// Reverting the elite advance keeps the aptitudes and rules owned
+	// before its purchase.
+	c = newCharacter(35)
+	c.Aptitudes["Psyker"] = Aptitude("Psyker")
+	c.Rules["Sanctioned"] = Rule{Name: "Sanctioned"}
+	err = c.ApplyUpgrade(Upgrade{Mark: MarkApply, Name: "Sanctioned Psyker", Line: 3}, universe)
+	if err == nil {
+		err = c.ApplyUpgrade(Upgrade{Mark: MarkRevert, Name: "Sanctioned Psyker", Cost: IntP(0), Line: 4}, universe)
+	}
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	_, aptitude = c.Aptitudes["Psyker"]
+	_, rule = c.Rules["Sanctioned"]
+	_, talent = c.Talents["Psyniscience"]
+	if !aptitude || !rule || talent {
+		t.Logf("Unexpected reverted character: %v", c)
+		t.Fail()
+	}
+
+	// Refunding the elite advance gives back its cost and reverts the
+	// upgrades it granted.
+	c = newCharacter(35)
+	c.options = Options{Refund: true}
+	err = c.ApplyUpgrade(Upgrade{Mark: MarkApply, Name: "Sanctioned Psyker", Line: 3}, universe)
+	if err == nil {
+		err = c.ApplyUpgrade(Upgrade{Mark: MarkRevert, Name: "Sanctioned Psyker", Line: 4}, universe)
+	}
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	_, aptitude = c.Aptitudes["Psyker"]
+	_, elite = c.Elites["Sanctioned Psyker"]
+	if c.Spent != 0 || aptitude || elite || c.Characteristics["WP"].Value != 35 || !c.options.Refund {
+		t.Logf("Unexpected refunded character: %d spent with %v", c.Spent, c)
+		t.Fail()
+	}
+
+	// The requirements must be met.
+	c = newCharacter(30)
+	err = c.ApplyUpgrade(Upgrade{Mark: MarkApply, Name: "Sanctioned Psyker", Line: 3}, universe)
+	if err == nil || err.(Error).Code != UnmetRequirement {
+		t.Logf("Expected unmet requirement, having %v", err)
+		t.Fail()
+	}
+
+	// A failing grant leaves the character untouched.
+	c = newCharacter(35, "Psyniscience")
+	before := c.clone()
+	err = c.ApplyUpgrade(Upgrade{Mark: MarkApply, Name: "Sanctioned Psyker", Line: 3}, universe)
+	if err == nil || err.(Error).Code != DuplicateUpgrade {
+		t.Logf("Expected duplicate upgrade, having %v", err)
+		t.Fail()
+	}
+	if !reflect.DeepEqual(*c, before) {
+		t.Logf("Unexpected partial grant: %v", c)
+		t.Fail()
+	}
+}
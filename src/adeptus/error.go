@@ -1,7 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
 )
 
 // ErrorCode holds the type of error return.
@@ -10,6 +14,9 @@ type ErrorCode int
 // Here is the list of defined error codes.
 const (
 	InvalidCharacterSheet ErrorCode = iota
+	InvalidInclude
+	UnreadableInclude
+	IncludeCycle
 
 	InvalidHeaderLine
 	EmptyHeaderKey
@@ -21,6 +28,13 @@ const (
 	InvalidSessionReward
 	DuplicateSessionReward
 	ForbidenRewardPosition
+	EmptySessionReference
+
+	UndefinedCampaignSession
+	AmbiguousCampaignSession
+	MissingCampaignSession
+	CampaignRewardMismatch
+	ForbidenCampaignReference
 
 	EmptyUpgrade
 	InvalidUpgradeFormat
@@ -37,9 +51,38 @@ const (
 	UndefinedTypeCost
 	UndefinedMatchCost
 	UndefinedTierCost
+	InvalidCostFormula
 
 	UndefinedCharacteristic
+	MissingCharacteristics
+	UnownedCharacteristic
 	UndefinedBackground
+	UndefinedUpgrade
+	ImplicitRule
+	OverspentExperience
+	UndefinedCareer
+	UndefinedAdvance
+	UnavailableAdvance
+	UnmetRequirement
+	DuplicateAptitude
+	IgnoredDuplicateAptitude
+	MissingAptitudeSubstitute
+	InvalidAptitudeSubstitute
+	UnusedAptitudeSubstitutes
+	GaugeOutOfBounds
+	MissingFollowUp
+	InvalidConditionExpiry
+	UnachievableCharacteristic
+	OverspentGenerationPoints
+	UnmatchedRefund
+
+	InvalidUniverse
+	UnnamedUniverseEntry
+	InvalidCostRule
+	InvalidAlignment
+	InvalidGauge
+	InvalidModifier
+	InvalidMapping
 
 	UnitTest
 )
@@ -48,36 +91,77 @@ const (
 var errorMsgs = map[ErrorCode]string{
 
 	InvalidCharacterSheet: `the character sheet requires at least a header block and a characteristic block`,
+	InvalidInclude:        `%s: the included file is not defined`,
+	UnreadableInclude:     `%s: unable to read the included file: %s`,
+	IncludeCycle:          `%s: the file %s is already included`,
+
+	InvalidHeaderLine:    `%s: the header line format is invalid`,
+	EmptyHeaderKey:       `%s: the header line key is empty`,
+	EmptyHeaderValue:     `%s: the header line value is empty`,
+	DuplicateHeaderLine:  `%s: the header line is already set`,
+	InvalidHeaderOptions: `%s: the background options are incorrect`,
+
+	UndefinedSessionDate:   `%s: the session date is not defined`,
+	InvalidSessionReward:   `%s: the session reward is invalid`,
+	DuplicateSessionReward: `%s: the session reward is already set`,
+	ForbidenRewardPosition: `%s: bad session reward position`,
+	EmptySessionReference:  `%s: the session reference is empty`,
 
-	InvalidHeaderLine:    `line %d: the header line format is invalid`,
-	EmptyHeaderKey:       `line %d: the header line key is empty`,
-	EmptyHeaderValue:     `line %d: the header line value is empty`,
-	DuplicateHeaderLine:  `line %d: the header line is already set`,
-	InvalidHeaderOptions: `line %d: the background options are incorrect`,
-
-	UndefinedSessionDate:   `line %d: the session date is not defined`,
-	InvalidSessionReward:   `line %d: the session reward is invalid`,
-	DuplicateSessionReward: `line %d: the session reward is already set`,
-	ForbidenRewardPosition: `line %d: bad session reward position`,
-
-	EmptyUpgrade:         `line %d: the upgrade name is not defined`,
-	InvalidUpgradeFormat: `line %d: the upgrade format is invalid`,
-	InvalidUpgradeValue:  `line %d: the upgrade value is invalid`,
-	InvalidUpgradeMark:   `line %d: the upgrade mark is invalid`,
-	InvalidUpgradeCost:   `line %d: the upgrade cost is invalid`,
-	DuplicateUpgradeCost: `line %d: the upgrade cost is already set`,
-	ForbidenUpgradeMark:  `line %d: the upgrade mark is forbiden`,
-	ForbidenCostPosition: `line %d: bad upgrade cost position`,
-	ForbidenUpgradeLoss:  `line %d: the upgrade is absent from sheet`,
-	ForbidenUpgradeValue: `line %d: the upgrade value is forbiden`,
-	DuplicateUpgrade:     `line %d: the upgrade is already set`,
+	UndefinedCampaignSession:  `%s: the campaign session %s is not defined`,
+	AmbiguousCampaignSession:  `%s: the campaign session %s is ambiguous`,
+	MissingCampaignSession:    `%s: the session is absent from the campaign log`,
+	CampaignRewardMismatch:    `%s: the session reward %d differs from the campaign log reward %d`,
+	ForbidenCampaignReference: `%s: the campaign log can't reference a session`,
+
+	EmptyUpgrade:         `%s: the upgrade name is not defined`,
+	InvalidUpgradeFormat: `%s: the upgrade format is invalid`,
+	InvalidUpgradeValue:  `%s: the upgrade value is invalid`,
+	InvalidUpgradeMark:   `%s: the upgrade mark is invalid`,
+	InvalidUpgradeCost:   `%s: the upgrade cost is invalid`,
+	DuplicateUpgradeCost: `%s: the upgrade cost is already set`,
+	ForbidenUpgradeMark:  `%s: the upgrade mark is forbiden`,
+	ForbidenCostPosition: `%s: bad upgrade cost position`,
+	ForbidenUpgradeLoss:  `%s: the upgrade is absent from sheet`,
+	ForbidenUpgradeValue: `%s: the upgrade value is forbiden`,
+	DuplicateUpgrade:     `%s: the upgrade is already set`,
 
 	UndefinedTypeCost:  `undefined cost for type %s`,
 	UndefinedMatchCost: `undefined cost for type %s with %d matching aptitudes`,
 	UndefinedTierCost:  `undefined cost for type %s with %d matching aptitudes on tier %d`,
+	InvalidCostFormula: `unable to compute the cost formula for type %s: %s`,
+
+	UndefinedCharacteristic: `%s: the characteristic is not defined`,
+	MissingCharacteristics:  `%s: the characteristic block misses %s`,
+	UnownedCharacteristic:   `the characteristic %s is absent from the characteristic block`,
+	UndefinedBackground:     `%s: the background %s: %s is not defined`,
+	UndefinedUpgrade:        `%s: the upgrade %s is not defined%s`,
+	ImplicitRule:            `%s: the upgrade %s is not defined and becomes a special rule`,
+	OverspentExperience:     `%s: the experience spent (%d) exceeds the experience earned (%d)`,
+	UndefinedCareer:         `%s: the career %s is not defined`,
+	UndefinedAdvance:        `%s: %s is not an advance of the career %s`,
+	UnavailableAdvance:      `%s: %s is an advance of the rank %s of the career %s, above the current rank %s`,
+	UnmetRequirement:        `%s: the requirement %s of %s is not met`,
+
+	DuplicateAptitude:         `%s: the aptitude %s given by %s is already owned`,
+	IgnoredDuplicateAptitude:  `%s: the aptitude %s given by %s is already owned and is ignored`,
+	MissingAptitudeSubstitute: `%s: the aptitude %s given by %s is already owned, choose a replacement between parenthesis`,
+	InvalidAptitudeSubstitute: `%s: the aptitude %s can't replace the aptitude %s`,
+	UnusedAptitudeSubstitutes: `%s: the replacement aptitudes %s of %s are unused`,
+	GaugeOutOfBounds:          `%s: the gauge %s can't be %d`,
+	MissingFollowUp:           `%s: the rule %s required by %s is missing from the session`,
+	InvalidConditionExpiry:    `%s: the condition expiry %s is invalid`,
+
+	UnachievableCharacteristic: `%s: the characteristic %s can't be %d at creation, only between %d and %d`,
+	OverspentGenerationPoints:  `%s: the characteristics cost %d points, exceeding the budget of %d points`,
+	UnmatchedRefund:            `%s: there is no earlier purchase of %s to refund`,
 
-	UndefinedCharacteristic: `line %d: the characteristic is not defined`,
-	UndefinedBackground:     `line %d: the background %s: %s is not defined`,
+	InvalidUniverse:      `%s: %s`,
+	UnnamedUniverseEntry: `%s: the %s entry #%d has no name`,
+	InvalidCostRule:      `%s: invalid cost rule for type %s: %s`,
+	InvalidAlignment:     `%s: invalid alignment: %s`,
+	InvalidGauge:         `%s: invalid gauge %s: %s`,
+	InvalidModifier:      `the modifier target %s of %s is not defined`,
+	InvalidMapping:       `%s: invalid VTT mapping: %s`,
 
 	UnitTest: `should not be seen outside unit testing`,
 }
@@ -104,3 +188,79 @@ func (e Error) Error() string {
 	}
 	return fmt.Sprintf(msg, e.vars...)
 }
+
+// Position returns the position of the error in the sheet, and a boolean
+// indicating if the error is located.
+func (e Error) Position() (Position, bool) {
+	for _, v := range e.vars {
+		if p, ok := v.(Position); ok {
+			return p, true
+		}
+	}
+	return Position{}, false
+}
+
+// Render returns the error message followed, if the error is located in a
+// readable file, by the offending line with the faulty part underlined.
+func Render(err error) string {
+	e, ok := err.(Error)
+	if !ok {
+		return err.Error()
+	}
+
+	position, found := e.Position()
+	if !found || len(position.File) == 0 {
+		return e.Error()
+	}
+
+	text, found := readLine(position.File, position.Line)
+	if !found {
+		return e.Error()
+	}
+
+	// Underline the span of the position, or the whole line if there is none.
+	start, span := position.Column-1, position.Span
+	if position.Column == 0 {
+		trimmed := strings.TrimLeft(text, " \t")
+		start = utf8.RuneCountInString(text) - utf8.RuneCountInString(trimmed)
+		span = utf8.RuneCountInString(strings.TrimRight(trimmed, " \t"))
+	}
+	if span == 0 {
+		span = 1
+	}
+
+	// Keep the tabulations before the underline so it is aligned with the
+	// text whatever the tab width.
+	margin := []rune{}
+	for i, r := range []rune(text) {
+		if i >= start {
+			break
+		}
+		if r != '\t' {
+			r = ' '
+		}
+		margin = append(margin, r)
+	}
+
+	return fmt.Sprintf("%s\n\t%s\n\t%s%s", e.Error(), text, string(margin), theme.Error(strings.Repeat("^", span)))
+}
+
+// readLine returns the line of the given file, and a boolean indicating if it
+// was found.
+func readLine(name string, number int) (string, bool) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for i := 1; scanner.Scan(); i++ {
+		if i == number {
+			return scanner.Text(), true
+		}
+	}
+	return "", false
+}
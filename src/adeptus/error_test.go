@@ -1,8 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
+
+	"github.com/fatih/color"
 )
 
 func Test_NewError(t *testing.T) {
@@ -34,3 +39,56 @@ func Test_Error_PanicIfNotFound(t *testing.T) {
 	err := NewError(ErrorCode(-1))
 	_ = err.Error()
 }
+
+func Test_Render(t *testing.T) {
+	color.NoColor = true
+
+	file, err := ioutil.TempFile("", "adeptus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	_, err = file.WriteString("Name: Sephiam\n\n\t+ Awareness [25O]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = file.Close()
+
+	cases := []struct {
+		in  error
+		out string
+	}{
+		{
+			in:  errors.New("plain error"),
+			out: "plain error",
+		},
+		{
+			in:  NewError(InvalidUpgradeCost, Position{Line: 3}),
+			out: "line 3: the upgrade cost is invalid",
+		},
+		{
+			in:  NewError(InvalidUpgradeCost, Position{File: file.Name(), Line: 3, Column: 14, Span: 5}),
+			out: file.Name() + ":3:14: the upgrade cost is invalid\n\t\t+ Awareness [25O]\n\t\t            ^^^^^",
+		},
+		{
+			in:  NewError(ForbidenUpgradeMark, Position{File: file.Name(), Line: 3}),
+			out: file.Name() + ":3: the upgrade mark is forbiden\n\t\t+ Awareness [25O]\n\t\t^^^^^^^^^^^^^^^^^",
+		},
+		{
+			in:  NewError(ForbidenUpgradeMark, Position{File: file.Name(), Line: 12}),
+			out: file.Name() + ":12: the upgrade mark is forbiden",
+		},
+	}
+
+	for i, c := range cases {
+		out := Render(c.in)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %q", c.out)
+			t.Logf("	Having %q", out)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// ExportHTML is the format of the HTML sheets, meant to be printed.
+const ExportHTML = "html"
+
+// TemplateDir is the directory of the universe holding the templates that
+// replace the default ones.
+const TemplateDir = "templates"
+
+// HTMLTemplate is the default template of the HTML sheet, laid out like the
+// official character sheet. The universe replaces it with a sheet.html file
+// in its templates directory.
+const HTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: Georgia, serif; margin: 1em auto; max-width: 60em; color: #222; }
+h1 { text-align: center; letter-spacing: .2em; text-transform: uppercase; }
+h2 { border-bottom: 2px solid #222; text-transform: uppercase; font-size: 1em; letter-spacing: .1em; }
+section { break-inside: avoid; }
+table { width: 100%; border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: .2em .4em; text-align: left; }
+th { background: #eee; }
+.header td { border: none; }
+.characteristics { display: grid; grid-template-columns: repeat(auto-fill, minmax(6em, 1fr)); gap: .5em; }
+.characteristic { border: 2px solid #222; text-align: center; padding: .3em; }
+.characteristic .name { font-weight: bold; }
+.characteristic .value { font-size: 1.6em; }
+.advance { display: inline-block; width: .8em; height: .8em; border: 1px solid #222; margin: 0 .1em; }
+.advance.taken { background: #222; }
+.number { text-align: right; }
+@media print { body { margin: 0; max-width: none; font-size: 10pt; } }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+
+<section>
+<table class="header">
+{{- range .Backgrounds}}
+<tr><th>{{title .Type}}</th><td>{{title .Name}}</td></tr>
+{{- end}}
+{{- if .Career}}
+<tr><th>Career</th><td>{{.Career}}{{if .Rank}} ({{.Rank}}){{end}}</td></tr>
+{{- end}}
+{{- if .Alignment}}
+<tr><th>Alignment</th><td>{{.Alignment}}</td></tr>
+{{- end}}
+<tr><th>Aptitudes</th><td>{{range $i, $a := .Aptitudes}}{{if $i}}, {{end}}{{title (printf "%s" $a)}}{{end}}</td></tr>
+<tr><th>Experience</th><td>{{.Spent}} spent of {{.Experience}} ({{.Remaining}} remaining)</td></tr>
+</table>
+</section>
+
+<section>
+<h2>Characteristics</h2>
+<div class="characteristics">
+{{- range .Characteristics}}
+<div class="characteristic">
+<div class="name">{{.Name}}</div>
+<div class="value">{{.Value}}</div>
+<div>{{range advances "characteristic" .Tier}}<span class="advance{{if .}} taken{{end}}"></span>{{end}}</div>
+{{- if .Modified}}
+<div>{{.Effective}}, bonus {{.Bonus}}</div>
+{{- end}}
+</div>
+{{- end}}
+</div>
+</section>
+
+{{- if .Gauges}}
+<section>
+<h2>Gauges</h2>
+<table>
+<tr><th>Gauge</th><th>Value</th><th>Current</th><th>Details</th></tr>
+{{- range .Gauges}}
+<tr><td>{{.Name}}</td><td class="number">{{.Value}}</td><td class="number">{{.Effective}}</td><td>{{range $i, $d := .Degradations}}{{if $i}}, {{end}}{{$d}}{{end}}{{if .Modifier}}{{if .Degradations}}, {{end}}conditions {{printf "%+d" .Modifier}}{{end}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- if .Skills}}
+<section>
+<h2>Skills</h2>
+<table>
+<tr><th>Skill</th><th>Advances</th><th>Bonus</th></tr>
+{{- range .Skills}}
+<tr><td>{{title .FullName}}</td><td>{{range advances "skill" .Tier}}<span class="advance{{if .}} taken{{end}}"></span>{{end}}</td><td class="number">{{printf "%+d" .Effective}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- if .Talents}}
+<section>
+<h2>Talents</h2>
+<table>
+{{- range .Talents}}
+<tr><td>{{title .FullName}}{{if ne .Value 1}} ({{.Value}}){{end}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- if .Spells}}
+<section>
+<h2>Spells</h2>
+<table>
+{{- range .Spells}}
+<tr><td>{{title .Name}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- if .Elites}}
+<section>
+<h2>Elite advances</h2>
+<table>
+{{- range .Elites}}
+<tr><td>{{title .Name}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- if .Conditions}}
+<section>
+<h2>Conditions</h2>
+<table>
+{{- range .Conditions}}
+<tr><td>{{title .Condition.Name}}</td><td>{{.}}</td><td>{{.Condition.Description}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- if .Rules}}
+<section>
+<h2>Rules</h2>
+<table>
+{{- range .Rules}}
+<tr><td>{{title .Name}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+{{- if .Modifiers}}
+<section>
+<h2>Modifiers</h2>
+<table>
+{{- range .Modifiers}}
+<tr><td>{{title .Modifier.Target}}</td><td>{{.Modifier}}</td><td>{{.Type}} {{title .Name}}</td></tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+
+<section>
+<h2>History</h2>
+<table>
+<tr><th>Session</th><th>Upgrade</th><th>Type</th><th>Cost</th></tr>
+{{- range .Balances}}
+<tr><th colspan="3">{{.Session.Date.Format "2006/01/02"}} {{.Session.Title}}</th><th class="number">{{.Spent}} / {{.Earned}}</th></tr>
+{{- range .Upgrades}}
+<tr><td></td><td>{{.Mark}} {{title .Name}}</td><td>{{.Type}}</td><td class="number">{{with .Cost}}{{.}}{{end}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+</section>
+</body>
+</html>
+`
+
+// readTemplate returns the content of the template of the given name in the
+// templates directory of the universe, or the fallback if there is none.
+func readTemplate(dir string, name string, fallback string) (string, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, TemplateDir, name))
+	if os.IsNotExist(err) {
+		return fallback, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// RenderHTML writes the HTML sheet of the character, using the template of
+// the universe directory if any.
+func RenderHTML(w io.Writer, c *Character, universe Universe, dir string) error {
+	raw, err := readTemplate(dir, "sheet.html", HTMLTemplate)
+	if err != nil {
+		return err
+	}
+	t, err := template.New("sheet.html").Funcs(templateFuncs(universe)).Parse(raw)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, c.View())
+}
+
+// exportPath returns the file the sheet is exported to: the given output, or
+// the sheet's path with the extension of the format. The sheet itself is never
+// overwritten, nor is an existing file unless the output is given or forced.
+func exportPath(sheet string, output string, extension string, force bool) (string, error) {
+	explicit := len(output) != 0
+	if !explicit {
+		output = strings.TrimSuffix(sheet, filepath.Ext(sheet)) + "." + extension
+	}
+	if output == "-" {
+		return output, nil
+	}
+
+	info, err := os.Stat(output)
+	if err != nil {
+		return output, nil
+	}
+	if s, err := os.Stat(sheet); err == nil && os.SameFile(info, s) {
+		return "", fmt.Errorf("the export %s would overwrite the character sheet", output)
+	}
+	if !explicit && !force {
+		return "", fmt.Errorf("the file %s already exists, use the output or force flags to overwrite it", output)
+	}
+	return output, nil
+}
+
+// Export writes the character sheet given on the command line in the format
+// given by the flags, next to the sheet unless an output is given.
+func Export(ctx *cli.Context) error {
+	var render func(io.Writer, *Character, Universe, string) error
+	var extension string
+	switch format := strings.ToLower(ctx.String("format")); format {
+	case ExportHTML:
+		render, extension = RenderHTML, "html"
+	case ExportMarkdown, "md":
+		render, extension = RenderMarkdown, "md"
+	case ExportVTT:
+		extension = "json"
+		render = func(w io.Writer, c *Character, universe Universe, dir string) error {
+			return RenderVTT(w, c, universe, dir, ctx.String("system"))
+		}
+	default:
+		return fmt.Errorf("%s unknown format %s", theme.Error("unable to export character:"), format)
+	}
+
+	universe, c, err := Bootstrap(ctx)
+	if err != nil {
+		return err
+	}
+
+	output, err := exportPath(ctx.Args().First(), ctx.String("output"), extension, ctx.Bool("force"))
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("unable to export character:"), err)
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("%s %s", theme.Error("unable to export character:"), err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+
+	err = render(w, c, universe, ctx.GlobalString("universe"))
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("unable to export character:"), err)
+	}
+
+	return nil
+}
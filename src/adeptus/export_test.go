@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func exportedCharacter(t *testing.T) (Universe, *Character) {
+	universe := Universe{
+		Characteristics: []Characteristic{
+			{Name: "WS"},
+		},
+		Skills: []Skill{
+			{Name: "Awareness"},
+		},
+		Costs: CostMatrix{
+			"characteristic": {0: {1: 100, 2: 200, 3: 300}},
+			"skill":          {0: {1: 100, 2: 200, 3: 300, 4: 400}},
+		},
+	}
+
+	sheet := Sheet{
+		Header: Header{Name: "Tom & Jerry"},
+		Characteristics: Characteristics{
+			{Mark: MarkSpecial, Name: "WS 30", Cost: IntP(0)},
+		},
+		Sessions: []Session{
+			{
+				Reward: IntP(1000),
+				Upgrades: []Upgrade{
+					{Mark: MarkApply, Name: "Awareness"},
+					{Mark: MarkApply, Name: "Awareness"},
+					{Mark: MarkApply, Name: "WS +5"},
+				},
+			},
+		},
+	}
+
+	c, err := NewCharacter(universe, sheet, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return universe, c
+}
+
+func Test_RenderHTML(t *testing.T) {
+	universe, c := exportedCharacter(t)
+
+	var out bytes.Buffer
+	err := RenderHTML(&out, c, universe, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"<h1>Tom &amp; Jerry</h1>",
+		`<div class="name">WS</div>`,
+		`<div class="value">35</div>`,
+		`<div><span class="advance taken"></span><span class="advance"></span><span class="advance"></span></div>`,
+		`<tr><td>Awareness</td><td><span class="advance taken"></span><span class="advance taken"></span><span class="advance"></span><span class="advance"></span></td><td class="number">&#43;10</td></tr>`,
+		"<td>400 spent of 1000 (600 remaining)</td>",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Logf("Missing %s in the sheet:", expected)
+			t.Logf("%s", out.String())
+			t.Fail()
+		}
+	}
+}
+
+func Test_RenderHTML_Universe(t *testing.T) {
+	universe, c := exportedCharacter(t)
+
+	dir, err := ioutil.TempDir("", "adeptus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	err = os.Mkdir(filepath.Join(dir, TemplateDir), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, TemplateDir, "sheet.html"), []byte(`{{.Name}}:{{range .Skills}} {{title .FullName}} {{len (advances "skill" .Tier)}}{{end}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = RenderHTML(&out, c, universe, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Tom &amp; Jerry: Awareness 4"
+	if out.String() != expected {
+		t.Logf("Unexpected sheet:")
+		t.Logf("	Expected %s", expected)
+		t.Logf("	Having %s", out.String())
+		t.Fail()
+	}
+}
+
+func Test_exportPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adeptus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	alice, bob, exported := filepath.Join(dir, "alice.md"), filepath.Join(dir, "bob.sheet"), filepath.Join(dir, "bob.html")
+	for _, f := range []string{alice, bob, exported} {
+		err = ioutil.WriteFile(f, []byte{}, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		sheet     string
+		output    string
+		extension string
+		force     bool
+		out       string
+		err       bool
+	}{
+		{sheet: bob, extension: "md", out: filepath.Join(dir, "bob.md")},
+		{sheet: bob, output: "-", extension: "md", out: "-"},
+		{sheet: alice, extension: "md", err: true},
+		{sheet: alice, extension: "md", force: true, err: true},
+		{sheet: bob, output: bob, extension: "html", err: true},
+		{sheet: bob, extension: "html", err: true},
+		{sheet: bob, extension: "html", force: true, out: exported},
+		{sheet: bob, output: exported, extension: "html", out: exported},
+	}
+
+	for i, c := range cases {
+		out, err := exportPath(c.sheet, c.output, c.extension, c.force)
+		if (err != nil) != c.err || out != c.out {
+			t.Logf("Unexpected output on case %d: %s, %v", i+1, out, err)
+			t.Fail()
+		}
+	}
+}
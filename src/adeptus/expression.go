@@ -0,0 +1,471 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expression is a compiled cost formula, evaluated against the pricing of an
+// upgrade.
+type expression func(Pricing) (int, error)
+
+// token is a lexical element of a cost formula.
+type token struct {
+	kind  tokenKind
+	text  string
+	value int
+}
+
+// tokenKind is the kind of a token.
+type tokenKind int
+
+// Kinds of token.
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+// operators are the operators of the formulas, longest first.
+var operators = []string{"<=", ">=", "==", "!=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!", "?", ":", "(", ")", ","}
+
+// tokenize splits the formula into tokens.
+func tokenize(formula string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(formula)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			value, err := strconv.Atoi(string(runes[i:j]))
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:j]), value: value})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:j])})
+			i = j
+
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : j])})
+			i = j + 1
+
+		default:
+			found := false
+			for _, operator := range operators {
+				if strings.HasPrefix(string(runes[i:]), operator) {
+					tokens = append(tokens, token{kind: tokenOperator, text: operator})
+					i += len([]rune(operator))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// parser is a recursive descent parser of cost formulas:
+//
+//	ternary := or [ "?" ternary ":" ternary ]
+//	or      := and { "||" and }
+//	and     := compare { "&&" compare }
+//	compare := sum [ ( "<" | "<=" | ">" | ">=" | "==" | "!=" ) sum ]
+//	sum     := product { ( "+" | "-" ) product }
+//	product := unary { ( "*" | "/" | "%" ) unary }
+//	unary   := ( "-" | "!" ) unary | primary
+//	primary := number | variable | function "(" arguments ")" | "(" ternary ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// compileFormula compiles the cost formula into an expression.
+func compileFormula(formula string) (expression, error) {
+	tokens, err := tokenize(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	e, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+
+	return e, nil
+}
+
+// peek returns the current token.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// accept consumes the current token if it is one of the given operators.
+func (p *parser) accept(operators ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+	for _, operator := range operators {
+		if t.text == operator {
+			p.pos++
+			return operator, true
+		}
+	}
+	return "", false
+}
+
+// expect consumes the current token, which must be the given operator.
+func (p *parser) expect(operator string) error {
+	if _, ok := p.accept(operator); !ok {
+		return fmt.Errorf("expected %q", operator)
+	}
+	return nil
+}
+
+func (p *parser) ternary() (expression, error) {
+	condition, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("?"); !ok {
+		return condition, nil
+	}
+
+	then, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(pricing Pricing) (int, error) {
+		c, err := condition(pricing)
+		if err != nil {
+			return 0, err
+		}
+		if c != 0 {
+			return then(pricing)
+		}
+		return otherwise(pricing)
+	}, nil
+}
+
+func (p *parser) or() (expression, error) {
+	return p.binary(p.and, "||")
+}
+
+func (p *parser) and() (expression, error) {
+	return p.binary(p.compare, "&&")
+}
+
+func (p *parser) compare() (expression, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	operator, ok := p.accept("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+
+	right, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	return operate(operator, left, right), nil
+}
+
+func (p *parser) sum() (expression, error) {
+	return p.binary(p.product, "+", "-")
+}
+
+func (p *parser) product() (expression, error) {
+	return p.binary(p.unary, "*", "/", "%")
+}
+
+// binary parses a left associative sequence of operands separated by the
+// given operators.
+func (p *parser) binary(operand func() (expression, error), operators ...string) (expression, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator, ok := p.accept(operators...)
+		if !ok {
+			return left, nil
+		}
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = operate(operator, left, right)
+	}
+}
+
+func (p *parser) unary() (expression, error) {
+	operator, ok := p.accept("-", "!")
+	if !ok {
+		return p.primary()
+	}
+
+	operand, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(pricing Pricing) (int, error) {
+		v, err := operand(pricing)
+		if err != nil {
+			return 0, err
+		}
+		if operator == "-" {
+			return -v, nil
+		}
+		return boolToInt(v == 0), nil
+	}, nil
+}
+
+func (p *parser) primary() (expression, error) {
+	t := p.peek()
+
+	switch t.kind {
+	case tokenNumber:
+		p.pos++
+		return func(Pricing) (int, error) {
+			return t.value, nil
+		}, nil
+
+	case tokenIdent:
+		p.pos++
+		if _, ok := p.accept("("); ok {
+			return p.call(t.text)
+		}
+		return variable(t.text)
+
+	case tokenOperator:
+		if t.text == "(" {
+			p.pos++
+			e, err := p.ternary()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	}
+
+	if t.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of formula")
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// call parses the arguments of the function of the given name, the opening
+// parenthesis being already consumed.
+func (p *parser) call(name string) (expression, error) {
+	switch strings.ToLower(name) {
+	case "gauge", "background", "characteristic":
+		t := p.peek()
+		if t.kind != tokenString {
+			return nil, fmt.Errorf("%s expects a quoted name", name)
+		}
+		p.pos++
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return lookup(strings.ToLower(name), t.text), nil
+
+	case "min", "max":
+		arguments := []expression{}
+		for {
+			argument, err := p.ternary()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		lowest := strings.ToLower(name) == "min"
+		return func(pricing Pricing) (int, error) {
+			var result int
+			for i, argument := range arguments {
+				v, err := argument(pricing)
+				if err != nil {
+					return 0, err
+				}
+				if i == 0 || (lowest && v < result) || (!lowest && v > result) {
+					result = v
+				}
+			}
+			return result, nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("undefined function %s", name)
+}
+
+// variable returns the expression giving the value of the variable of the
+// given name.
+func variable(name string) (expression, error) {
+	switch strings.ToLower(name) {
+	case "tier":
+		return func(pricing Pricing) (int, error) {
+			return pricing.Tier, nil
+		}, nil
+	case "matches":
+		return func(pricing Pricing) (int, error) {
+			return pricing.Matches, nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("undefined variable %s", name)
+}
+
+// lookup returns the expression giving the value of the character's gauge or
+// characteristic of the given name, or 1 if the character has the background
+// of the given name and 0 otherwise.
+func lookup(function string, name string) expression {
+	return func(pricing Pricing) (int, error) {
+		switch function {
+		case "gauge":
+			for _, gauge := range pricing.Character.Gauges {
+				if strings.EqualFold(gauge.Name, name) {
+					return gauge.Value, nil
+				}
+			}
+		case "characteristic":
+			for _, characteristic := range pricing.Character.Characteristics {
+				if strings.EqualFold(characteristic.Name, name) {
+					return characteristic.Value, nil
+				}
+			}
+		case "background":
+			for _, background := range pricing.Character.Backgrounds {
+				if strings.EqualFold(background.Name, name) {
+					return 1, nil
+				}
+			}
+		}
+		return 0, nil
+	}
+}
+
+// operate returns the expression applying the binary operator to the
+// operands.
+func operate(operator string, left, right expression) expression {
+	return func(pricing Pricing) (int, error) {
+		l, err := left(pricing)
+		if err != nil {
+			return 0, err
+		}
+
+		// Logical operators short-circuit.
+		switch {
+		case operator == "&&" && l == 0:
+			return 0, nil
+		case operator == "||" && l != 0:
+			return 1, nil
+		}
+
+		r, err := right(pricing)
+		if err != nil {
+			return 0, err
+		}
+
+		switch operator {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/", "%":
+			if r == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			if operator == "/" {
+				return l / r, nil
+			}
+			return l % r, nil
+		case "<":
+			return boolToInt(l < r), nil
+		case "<=":
+			return boolToInt(l <= r), nil
+		case ">":
+			return boolToInt(l > r), nil
+		case ">=":
+			return boolToInt(l >= r), nil
+		case "==":
+			return boolToInt(l == r), nil
+		case "!=":
+			return boolToInt(l != r), nil
+		case "&&", "||":
+			return boolToInt(r != 0), nil
+		}
+
+		return 0, fmt.Errorf("undefined operator %s", operator)
+	}
+}
+
+// boolToInt returns 1 if the value is true, 0 otherwise.
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_compileFormula(t *testing.T) {
+	pricing := Pricing{
+		Type:    "talent",
+		Matches: 1,
+		Tier:    2,
+		Character: Character{
+			Backgrounds: map[string]Background{
+				"seeker": {Type: "role", Name: "Seeker"},
+			},
+			Gauges: map[string]Gauge{
+				"Corruption": {Name: "Corruption", Value: 12},
+			},
+			Characteristics: map[string]Characteristic{
+				"WS": {Name: "WS", Value: 35},
+			},
+		},
+	}
+
+	cases := []struct {
+		in      string
+		out     int
+		compile bool
+		eval    bool
+	}{
+		{in: `100`, out: 100},
+		{in: `tier * 300 - matches * 100`, out: 500},
+		{in: `(tier + 1) * 100`, out: 300},
+		{in: `-tier + 10 / 3 % 2`, out: -1},
+		{in: `tier >= 2 && matches == 1`, out: 1},
+		{in: `tier < 2 || !matches`, out: 0},
+		{in: `matches == 2 ? 100 : matches == 1 ? 200 : 300`, out: 200},
+		{in: `gauge("corruption") + characteristic("WS")`, out: 47},
+		{in: `gauge("Insanity")`, out: 0},
+		{in: `background("Seeker") * 50 + background("Warrior")`, out: 50},
+		{in: `max(tier, matches, 3) + min(tier, matches)`, out: 4},
+		{in: `100 / (tier - 2)`, eval: true},
+		{in: `level * 100`, compile: true},
+		{in: `round(tier)`, compile: true},
+		{in: `gauge(Corruption)`, compile: true},
+		{in: `(tier`, compile: true},
+		{in: `tier tier`, compile: true},
+		{in: `tier ? 1`, compile: true},
+		{in: `"unterminated`, compile: true},
+		{in: `tier $ 2`, compile: true},
+		{in: ``, compile: true},
+	}
+
+	for i, c := range cases {
+		e, err := compileFormula(c.in)
+		if (err != nil) != c.compile {
+			if c.compile {
+				t.Logf("Expected compilation error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected compilation error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		out, err := e(pricing)
+		if (err != nil) != c.eval {
+			if c.eval {
+				t.Logf("Expected evaluation error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected evaluation error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+			continue
+		}
+
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %d", c.out)
+			t.Logf("	Having %d", out)
+			t.Fail()
+		}
+	}
+}
@@ -11,19 +11,97 @@ import (
 // and the type of attributes that generally aren't bought by spending
 // experience points.
 type Gauge struct {
-	Name  string `yaml:"name"`
-	Value int    `yaml:"-"`
-	XP    int    `yaml:"xp"`
+	Name       string      `yaml:"name"`
+	Value      int         `yaml:"-"`
+	XP         int         `yaml:"xp"`
+	Min        *int        `yaml:"min"`
+	Max        *int        `yaml:"max"`
+	Thresholds []Threshold `yaml:"thresholds"`
+	Tracks     []Track     `yaml:"tracks"`
+
+	// Degradation is the value of each track of the character's gauge.
+	Degradation map[string]int `yaml:"-"`
+
+	// track is the name of the track changed by the upgrade, if any.
+	track string
+}
+
+// Threshold is a value of a gauge which grants or requires a special rule
+// when reached, either once or at every multiple of its value.
+type Threshold struct {
+	At      int    `yaml:"at"`
+	Every   int    `yaml:"every"`
+	Grant   string `yaml:"grant"`
+	Require string `yaml:"require"`
+}
+
+// Track is a degradation of a gauge, like the fate points burned or spent,
+// which lowers its current value without changing the gauge itself.
+type Track struct {
+	Name string `yaml:"name"`
+
+	// Recovered tracks are reset at the beginning of each session.
+	Recovered bool `yaml:"recovered"`
 }
 
-// Cost returns 0, a gauge has no calculated cost.
+// Crossing is a threshold of a gauge reached by an upgrade.
+type Crossing struct {
+	Gauge     string
+	Value     int
+	Threshold Threshold
+	Upgrade   Upgrade
+}
+
+// String returns the name of the gauge and the value reached.
+func (c Crossing) String() string {
+	return fmt.Sprintf("%s %d", c.Gauge, c.Value)
+}
+
+// Check returns an error if the gauge is inconsistent.
+func (g Gauge) Check() error {
+	if g.Min != nil && g.Max != nil && *g.Min > *g.Max {
+		return fmt.Errorf("the minimum %d exceeds the maximum %d", *g.Min, *g.Max)
+	}
+
+	for _, t := range g.Thresholds {
+		if (t.At == 0) == (t.Every == 0) {
+			return fmt.Errorf("exactly one of at or every must be defined for each threshold")
+		}
+		if t.Every < 0 {
+			return fmt.Errorf("the threshold every %d is not positive", t.Every)
+		}
+		if (len(t.Grant) == 0) == (len(t.Require) == 0) {
+			return fmt.Errorf("exactly one of grant or require must be defined for each threshold")
+		}
+	}
+
+	names := map[string]struct{}{}
+	for _, t := range g.Tracks {
+		name := strings.ToLower(t.Name)
+		if len(strings.TrimSpace(name)) == 0 {
+			return fmt.Errorf("unnamed track")
+		}
+		if _, found := names[name]; found {
+			return fmt.Errorf("duplicate track %s", t.Name)
+		}
+		names[name] = struct{}{}
+	}
+
+	return nil
+}
+
+// Cost returns the experience cost of the gauge's value, or 0 if the
+// upgrade changes one of its tracks.
 func (g Gauge) Cost(u Universe, character Character) (int, error) {
+	if len(g.track) != 0 {
+		return 0, nil
+	}
 
 	return g.XP * g.Value, nil
 }
 
 // Apply applys the upgrade on the character:
-// * affect the gauge value
+// * affect the gauge value, or the value of one of its tracks
 // * does not affect the character's XP
 func (g Gauge) Apply(character *Character, upgrade Upgrade) error {
 
@@ -32,20 +110,31 @@ func (g Gauge) Apply(character *Character, upgrade Upgrade) error {
 	if !found {
 		old = g
 		old.Value = 0
+		old.track = ""
 	}
 
-	// Parse the gauge's upgrade value.
-	raw := strings.TrimSpace(strings.TrimLeft(upgrade.Name, g.Name))
+	// Parse the gauge's upgrade value, the last field of the upgrade.
+	fields := strings.Fields(upgrade.Name)
+	raw := fields[len(fields)-1]
 	value, err := strconv.Atoi(raw)
 	if err != nil {
-		return NewError(InvalidUpgradeValue, upgrade.Line)
+		return NewError(InvalidUpgradeValue, upgrade.Position())
 	}
 
 	// Update the gauge value.
 	if !(strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-")) {
-		return NewError(ForbidenUpgradeValue, upgrade.Line)
+		return NewError(ForbidenUpgradeValue, upgrade.Position())
+	}
+
+	if len(g.track) != 0 {
+		return old.degrade(character, g.track, value, upgrade)
+	}
+
+	value += old.Value
+	if (g.Min != nil && value < *g.Min) || (g.Max != nil && value > *g.Max) {
+		return NewError(GaugeOutOfBounds, upgrade.Position(), g.Name, value)
 	}
-	old.Value += value
+	old.Value = value
 
 	// Set the gauge back on the map.
 	character.Gauges[g.Name] = old
@@ -53,6 +142,90 @@ func (g Gauge) Apply(character *Character, upgrade Upgrade) error {
 	return nil
 }
 
+// degrade changes the value of a track of the character's gauge, which can't
+// be negative nor exceed the gauge's value along with the other tracks.
+func (g Gauge) degrade(character *Character, track string, value int, upgrade Upgrade) error {
+	value += g.Degradation[track]
+	if value < 0 || g.Degraded()-g.Degradation[track]+value > g.Value {
+		return NewError(GaugeOutOfBounds, upgrade.Position(), g.Name+" "+track, value)
+	}
+
+	// Copy the tracks so the previous states of the character are unchanged.
+	degradation := make(map[string]int)
+	for name, v := range g.Degradation {
+		degradation[name] = v
+	}
+	degradation[track] = value
+	g.Degradation = degradation
+
+	character.Gauges[g.Name] = g
+
+	return nil
+}
+
+// Degraded returns the sum of the values of the tracks of the gauge.
+func (g Gauge) Degraded() int {
+	sum := 0
+	for _, v := range g.Degradation {
+		sum += v
+	}
+	return sum
+}
+
+// Current returns the value of the gauge, lowered by its tracks.
+func (g Gauge) Current() int {
+	return g.Value - g.Degraded()
+}
+
+// Recover returns the gauge with its recovered tracks reset.
+func (g Gauge) Recover() Gauge {
+	recovered := false
+	for _, t := range g.Tracks {
+		if t.Recovered && g.Degradation[t.Name] != 0 {
+			recovered = true
+		}
+	}
+	if !recovered {
+		return g
+	}
+
+	degradation := make(map[string]int)
+	for name, v := range g.Degradation {
+		degradation[name] = v
+	}
+	for _, t := range g.Tracks {
+		if t.Recovered {
+			delete(degradation, t.Name)
+		}
+	}
+	g.Degradation = degradation
+
+	return g
+}
+
+// Cross returns the thresholds reached when the gauge's value goes up from
+// one value to another, in the order of the thresholds.
+func (g Gauge) Cross(from int, to int, upgrade Upgrade) []Crossing {
+	crossings := []Crossing{}
+	for _, t := range g.Thresholds {
+		if t.Every == 0 {
+			if from < t.At && t.At <= to {
+				crossings = append(crossings, Crossing{Gauge: g.Name, Value: t.At, Threshold: t, Upgrade: upgrade})
+			}
+			continue
+		}
+
+		start := (from/t.Every + 1) * t.Every
+		if start < t.Every {
+			start = t.Every
+		}
+		for v := start; v <= to; v += t.Every {
+			crossings = append(crossings, Crossing{Gauge: g.Name, Value: v, Threshold: t, Upgrade: upgrade})
+		}
+	}
+	return crossings
+}
+
 // DefaultName returns the default upgrade name.
 func (g Gauge) DefaultName() string {
 	return fmt.Sprintf("%s +%d", g.Name, 1)
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Gauge_Apply(t *testing.T) {
+	universe := Universe{
+		Gauges: []Gauge{
+			{
+				Name: "Fate",
+				Min:  IntP(0),
+				Max:  IntP(5),
+				Tracks: []Track{
+					{Name: "burned"},
+					{Name: "spent", Recovered: true},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		in          []string
+		value       int
+		degradation map[string]int
+		err         bool
+	}{
+		{
+			in:    []string{"Fate +3"},
+			value: 3,
+		},
+		{
+			in:  []string{"Fate +6"},
+			err: true,
+		},
+		{
+			in:  []string{"Fate +1", "Fate -2"},
+			err: true,
+		},
+		{
+			in:          []string{"Fate +3", "Fate burned +1", "Fate spent +1"},
+			value:       3,
+			degradation: map[string]int{"burned": 1, "spent": 1},
+		},
+		{
+			in:  []string{"Fate +2", "Fate burned +1", "Fate spent +2"},
+			err: true,
+		},
+		{
+			in:  []string{"Fate +2", "Fate spent -1"},
+			err: true,
+		},
+	}
+
+	for i, c := range cases {
+		character := Character{Gauges: map[string]Gauge{}}
+
+		var err error
+		for _, name := range c.in {
+			upgrade := Upgrade{Mark: MarkSpecial, Name: name}
+			gauge, found := universe.FindGauge(upgrade)
+			if !found {
+				t.Logf("Undefined gauge %s on case %d", name, i+1)
+				t.FailNow()
+			}
+			err = gauge.Apply(&character, upgrade)
+			if err != nil {
+				break
+			}
+		}
+
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+			continue
+		}
+		if c.err {
+			continue
+		}
+
+		out := character.Gauges["Fate"]
+		if out.Value != c.value || !reflect.DeepEqual(out.Degradation, c.degradation) {
+			t.Logf("Unexpected gauge on case %d: %v", i+1, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Gauge_Recover(t *testing.T) {
+	gauge := Gauge{
+		Name:        "Fate",
+		Value:       3,
+		Tracks:      []Track{{Name: "burned"}, {Name: "spent", Recovered: true}},
+		Degradation: map[string]int{"burned": 1, "spent": 1},
+	}
+
+	recovered := gauge.Recover()
+	if recovered.Current() != 2 || gauge.Current() != 1 {
+		t.Logf("Unexpected recovery: %d from %d", recovered.Current(), gauge.Current())
+		t.Fail()
+	}
+}
+
+func Test_Gauge_Cross(t *testing.T) {
+	gauge := Gauge{
+		Name: "Corruption",
+		Thresholds: []Threshold{
+			{Every: 10, Require: "Malignancy"},
+			{At: 30, Grant: "Mutation"},
+		},
+	}
+
+	cases := []struct {
+		from     int
+		to       int
+		expected []int
+	}{
+		{from: 0, to: 9, expected: []int{}},
+		{from: 5, to: 10, expected: []int{10}},
+		{from: 10, to: 15, expected: []int{}},
+		{from: 9, to: 31, expected: []int{10, 20, 30, 30}},
+		{from: 20, to: 10, expected: []int{}},
+		{from: -5, to: 10, expected: []int{10}},
+	}
+
+	for i, c := range cases {
+		out := []int{}
+		for _, crossing := range gauge.Cross(c.from, c.to, Upgrade{}) {
+			out = append(out, crossing.Value)
+		}
+		if !reflect.DeepEqual(out, c.expected) {
+			t.Logf("Unexpected crossings on case %d: expected %v, having %v", i+1, c.expected, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_NewCharacter_Thresholds(t *testing.T) {
+	universe := Universe{
+		Gauges: []Gauge{
+			{
+				Name: "Corruption",
+				Thresholds: []Threshold{
+					{Every: 10, Require: "Malignancy"},
+				},
+			},
+			{
+				Name: "Insanity",
+				Thresholds: []Threshold{
+					{At: 40, Grant: "Mental Disorder"},
+				},
+			},
+		},
+	}
+
+	session := func(upgrades ...string) Session {
+		s := Session{Reward: IntP(0), Line: 1}
+		for i, name := range upgrades {
+			s.Upgrades = append(s.Upgrades, Upgrade{Mark: MarkSpecial, Name: name, Cost: IntP(0), Line: i + 2})
+		}
+		return s
+	}
+
+	// The granted rules are applied along with the gauge.
+	c, err := NewCharacter(universe, Sheet{Sessions: []Session{session("Insanity +45")}}, Options{})
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	if _, found := c.Rules["Mental Disorder"]; !found || len(c.History) != 2 || c.History[1].Threshold != "Insanity 40" {
+		t.Logf("Unexpected granted rule: %v", c.History)
+		t.Fail()
+	}
+	if len(c.Crossings) != 1 || len(c.History[0].Crossings) != 1 {
+		t.Logf("Unexpected crossings: %v", c.Crossings)
+		t.Fail()
+	}
+
+	// The required rules must follow in the session.
+	sessions := []Session{session("Corruption +12")}
+	sessions[0].Upgrades = append(sessions[0].Upgrades, Upgrade{Mark: MarkApply, Name: "Rule: Malignancy", Line: 3})
+	_, err = NewCharacter(universe, Sheet{Sessions: sessions}, Options{})
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.Fail()
+	}
+
+	_, err = NewCharacter(universe, Sheet{Sessions: []Session{session("Corruption +22"), session()}}, Options{})
+	if err == nil || err.(Error).Code != MissingFollowUp {
+		t.Logf("Expected missing follow-up, having %v", err)
+		t.Fail()
+	}
+}
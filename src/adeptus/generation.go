@@ -0,0 +1,380 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bradfitz/slice"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Generation methods of the characteristics.
+const (
+	GenerationRoll   = "roll"
+	GenerationPoints = "points"
+)
+
+// Generation describes how the characteristics are obtained at the creation
+// of a character: rolled, each one being the base plus the dice, or bought,
+// each one being the base plus the points spent on it.
+type Generation struct {
+	Method string `yaml:"method"`
+	Base   int    `yaml:"base"`
+	Dice   string `yaml:"dice"`
+	Points int    `yaml:"points"`
+	Max    int    `yaml:"max"`
+
+	// Bonus and Malus are the characteristics rolled with an extra die,
+	// dropping respectively the lowest and the highest.
+	Bonus []string `yaml:"bonus"`
+	Malus []string `yaml:"malus"`
+
+	// Rerolls is the number of characteristics that may be rolled again,
+	// keeping the second roll.
+	Rerolls int `yaml:"rerolls"`
+}
+
+// Over returns the generation method completed by the given one, the unset
+// fields taking its values.
+func (g Generation) Over(other Generation) Generation {
+	if len(g.Method) == 0 {
+		g.Method = other.Method
+	}
+	if g.Base == 0 {
+		g.Base = other.Base
+	}
+	if len(g.Dice) == 0 {
+		g.Dice = other.Dice
+	}
+	if g.Points == 0 {
+		g.Points = other.Points
+	}
+	if g.Max == 0 {
+		g.Max = other.Max
+	}
+	if g.Bonus == nil {
+		g.Bonus = other.Bonus
+	}
+	if g.Malus == nil {
+		g.Malus = other.Malus
+	}
+	if g.Rerolls == 0 {
+		g.Rerolls = other.Rerolls
+	}
+	return g
+}
+
+// GenerationOf returns the generation method of a character having the given
+// backgrounds: the one of the first background defining one, completed by
+// the one of the universe.
+func (u Universe) GenerationOf(backgrounds []Background) Generation {
+	for _, background := range backgrounds {
+		if background.Generation != nil {
+			return background.Generation.Over(u.Generation)
+		}
+	}
+	return u.Generation
+}
+
+// Check returns an error if the generation method is inconsistent.
+func (g Generation) Check() error {
+	switch g.Method {
+	case "":
+	case GenerationRoll:
+		_, err := ParseDice(g.Dice)
+		if err != nil {
+			return err
+		}
+	case GenerationPoints:
+		if g.Points <= 0 {
+			return fmt.Errorf("the points budget must be positive")
+		}
+	default:
+		return fmt.Errorf("undefined generation method %s", g.Method)
+	}
+	return nil
+}
+
+// CheckGenerations returns an error if the generation method of a
+// background, completed by the universe's, is invalid.
+func (u Universe) CheckGenerations() error {
+	for _, backgrounds := range u.Backgrounds {
+		for _, b := range backgrounds {
+			if b.Generation == nil {
+				continue
+			}
+			err := b.Generation.Over(u.Generation).Check()
+			if err != nil {
+				return fmt.Errorf("%s: %s", b.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Range returns the lowest and highest values of a characteristic at
+// creation, and false if the generation method doesn't bound them.
+func (g Generation) Range() (int, int, bool) {
+	switch g.Method {
+	case GenerationRoll:
+		dice, _ := ParseDice(g.Dice)
+		return g.Base + dice.Count + dice.Modifier, g.Base + dice.Count*dice.Sides + dice.Modifier, true
+	case GenerationPoints:
+		max := g.Points
+		if g.Max != 0 && g.Max < max {
+			max = g.Max
+		}
+		return g.Base, g.Base + max, true
+	}
+	return 0, 0, false
+}
+
+// Validate returns a warning for each characteristic of the block which
+// can't be obtained by the generation method, and if the points spent
+// exceed the budget.
+func (g Generation) Validate(characteristics Characteristics) []error {
+	warnings := []error{}
+	min, max, bounded := g.Range()
+	if !bounded {
+		return warnings
+	}
+
+	spent := 0
+	for _, upgrade := range characteristics {
+		fields := strings.Fields(upgrade.Name)
+		value, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			continue
+		}
+
+		if value < min || value > max {
+			warnings = append(warnings, NewError(UnachievableCharacteristic, upgrade.Position(), strings.Join(fields[:len(fields)-1], " "), value, min, max))
+		}
+		spent += value - g.Base
+	}
+
+	if g.Method == GenerationPoints && spent > g.Points && len(characteristics) != 0 {
+		warnings = append(warnings, NewError(OverspentGenerationPoints, characteristics[0].Position(), spent, g.Points))
+	}
+
+	return warnings
+}
+
+// Roll is the roll of a characteristic at creation.
+type Roll struct {
+	Characteristic string
+	Dice           []int
+	Kept           []int
+	Value          int
+}
+
+// String returns the dice rolled, the dropped ones between parenthesis.
+func (r Roll) String() string {
+	kept := append([]int{}, r.Kept...)
+	dice := []string{}
+	for _, d := range r.Dice {
+		dropped := true
+		for i, k := range kept {
+			if k == d {
+				kept = append(kept[:i], kept[i+1:]...)
+				dropped = false
+				break
+			}
+		}
+		if dropped {
+			dice = append(dice, fmt.Sprintf("(%d)", d))
+		} else {
+			dice = append(dice, strconv.Itoa(d))
+		}
+	}
+	return strings.Join(dice, " ")
+}
+
+// Roll returns the roll of the given characteristic with the generation
+// method: the bonus characteristics roll an extra die and drop the lowest,
+// the malus ones roll an extra die and drop the highest.
+func (g Generation) Roll(characteristic string, r *rand.Rand) Roll {
+	dice, _ := ParseDice(g.Dice)
+
+	bonus := in(strings.ToLower(characteristic), lower(g.Bonus))
+	malus := in(strings.ToLower(characteristic), lower(g.Malus))
+
+	count := dice.Count
+	if bonus != malus {
+		count++
+	}
+
+	roll := Roll{Characteristic: characteristic}
+	for i := 0; i < count; i++ {
+		roll.Dice = append(roll.Dice, r.Intn(dice.Sides)+1)
+	}
+
+	kept := append([]int{}, roll.Dice...)
+	slice.Sort(kept, func(i, j int) bool {
+		return kept[i] < kept[j]
+	})
+	switch {
+	case bonus && !malus:
+		kept = kept[1:]
+	case malus && !bonus:
+		kept = kept[:len(kept)-1]
+	}
+	roll.Kept = kept
+
+	roll.Value = g.Base + dice.Modifier
+	for _, d := range kept {
+		roll.Value += d
+	}
+	return roll
+}
+
+// Dice is a roll of dice, like 2d10+5.
+type Dice struct {
+	Count    int
+	Sides    int
+	Modifier int
+}
+
+// ParseDice returns the dice described by the given text.
+func ParseDice(raw string) (Dice, error) {
+	text := strings.ToLower(strings.Replace(raw, " ", "", -1))
+
+	// Split the modifier.
+	modifier := 0
+	if i := strings.IndexAny(text, "+-"); i != -1 {
+		m, err := strconv.Atoi(text[i:])
+		if err != nil {
+			return Dice{}, fmt.Errorf("invalid dice %s", raw)
+		}
+		modifier, text = m, text[:i]
+	}
+
+	fields := strings.Split(text, "d")
+	if len(fields) != 2 {
+		return Dice{}, fmt.Errorf("invalid dice %s", raw)
+	}
+
+	count := 1
+	if len(fields[0]) != 0 {
+		c, err := strconv.Atoi(fields[0])
+		if err != nil || c <= 0 {
+			return Dice{}, fmt.Errorf("invalid dice %s", raw)
+		}
+		count = c
+	}
+
+	sides, err := strconv.Atoi(fields[1])
+	if err != nil || sides <= 0 {
+		return Dice{}, fmt.Errorf("invalid dice %s", raw)
+	}
+
+	return Dice{Count: count, Sides: sides, Modifier: modifier}, nil
+}
+
+// Roll returns the sum of the dice rolled with the given source.
+func (d Dice) Roll(r *rand.Rand) int {
+	sum := d.Modifier
+	for i := 0; i < d.Count; i++ {
+		sum += r.Intn(d.Sides) + 1
+	}
+	return sum
+}
+
+// String returns the dice in the usual notation.
+func (d Dice) String() string {
+	if d.Modifier == 0 {
+		return fmt.Sprintf("%dd%d", d.Count, d.Sides)
+	}
+	return fmt.Sprintf("%dd%d%+d", d.Count, d.Sides, d.Modifier)
+}
+
+// RollCharacteristics rolls the characteristics of a new character with the
+// generation method of the backgrounds given on the command line, and
+// displays them as the characteristics block of a sheet, followed by the
+// dice rolled.
+func RollCharacteristics(ctx *cli.Context) error {
+	universe, err := LoadUniverse(ctx.GlobalString("universe"))
+	if err != nil {
+		return err
+	}
+
+	backgrounds := []Background{}
+	for _, raw := range ctx.StringSlice("background") {
+		typ, label, ok := splitFlag(raw)
+		if !ok {
+			return fmt.Errorf("%s invalid background %s", theme.Error("unable to roll characteristics:"), raw)
+		}
+		background, found := universe.FindBackground(typ, label)
+		if !found {
+			return fmt.Errorf("%s undefined background %s", theme.Error("unable to roll characteristics:"), raw)
+		}
+		backgrounds = append(backgrounds, background)
+	}
+
+	generation := universe.GenerationOf(backgrounds)
+	if generation.Method != GenerationRoll {
+		return fmt.Errorf("%s the characteristics are not rolled", theme.Error("unable to roll characteristics:"))
+	}
+
+	rerolls := ctx.StringSlice("reroll")
+	if len(rerolls) > generation.Rerolls {
+		return fmt.Errorf("%s at most %d rerolls are allowed", theme.Error("unable to roll characteristics:"), generation.Rerolls)
+	}
+
+	seed := time.Now().UnixNano()
+	if ctx.IsSet("seed") {
+		seed = ctx.Int64("seed")
+	}
+	r := rand.New(rand.NewSource(seed))
+
+	// Roll each characteristic, then the rerolls, so the first rolls only
+	// depend on the seed.
+	rolls := []Roll{}
+	for _, characteristic := range universe.Characteristics {
+		rolls = append(rolls, generation.Roll(characteristic.Name, r))
+	}
+
+	rerolled := make(map[int]Roll)
+	for _, name := range rerolls {
+		index := -1
+		for i, roll := range rolls {
+			if strings.EqualFold(roll.Characteristic, name) {
+				index = i
+			}
+		}
+		if index == -1 {
+			return fmt.Errorf("%s undefined characteristic %s", theme.Error("unable to roll characteristics:"), name)
+		}
+		if _, found := rerolled[index]; found {
+			return fmt.Errorf("%s the characteristic %s is already rerolled", theme.Error("unable to roll characteristics:"), name)
+		}
+		rerolled[index] = generation.Roll(rolls[index].Characteristic, r)
+	}
+
+	// Print the characteristics block.
+	for i, roll := range rolls {
+		if reroll, found := rerolled[i]; found {
+			roll = reroll
+		}
+		fmt.Printf("%s %d\n", roll.Characteristic, roll.Value)
+	}
+
+	// Print the dice.
+	fmt.Printf("\n%s (%s)\n", theme.Title("Rolls"), theme.Value(generation.Dice))
+	w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+	for i, roll := range rolls {
+		if reroll, found := rerolled[i]; found {
+			fmt.Fprintf(w, "%s\t%s\t%s %s\n", roll.Characteristic, roll, theme.Warning("rerolled"), reroll)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", roll.Characteristic, roll)
+	}
+	w.Flush()
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func Test_ParseDice(t *testing.T) {
+	cases := []struct {
+		in  string
+		out Dice
+		err bool
+	}{
+		{in: "2d10", out: Dice{Count: 2, Sides: 10}},
+		{in: "d100", out: Dice{Count: 1, Sides: 100}},
+		{in: "3D6+2", out: Dice{Count: 3, Sides: 6, Modifier: 2}},
+		{in: "1d5 - 1", out: Dice{Count: 1, Sides: 5, Modifier: -1}},
+		{in: "2x10", err: true},
+		{in: "0d10", err: true},
+		{in: "2d", err: true},
+	}
+
+	for i, c := range cases {
+		out, err := ParseDice(c.in)
+		if (err != nil) != c.err || out != c.out {
+			t.Logf("Unexpected result on case %d: %v, %v", i+1, out, err)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Generation_Roll(t *testing.T) {
+	generation := Generation{
+		Method: GenerationRoll,
+		Base:   20,
+		Dice:   "2d10",
+		Bonus:  []string{"STR"},
+		Malus:  []string{"int"},
+	}
+	r := rand.New(rand.NewSource(1))
+
+	cases := []struct {
+		characteristic string
+		dropped        func(dice []int) int
+	}{
+		{characteristic: "WS", dropped: func(dice []int) int { return 0 }},
+		{characteristic: "STR", dropped: func(dice []int) int { return lowest(dice) }},
+		{characteristic: "INT", dropped: func(dice []int) int { return -lowest([]int{-dice[0], -dice[1], -dice[2]}) }},
+	}
+
+	for i, c := range cases {
+		roll := generation.Roll(c.characteristic, r)
+		sum := 20 - c.dropped(roll.Dice)
+		for _, d := range roll.Dice {
+			sum += d
+		}
+		if len(roll.Kept) != 2 || roll.Value != sum {
+			t.Logf("Unexpected roll on case %d: %v", i+1, roll)
+			t.Fail()
+		}
+	}
+}
+
+func lowest(values []int) int {
+	m := values[0]
+	for _, v := range values {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func Test_Universe_GenerationOf(t *testing.T) {
+	universe := Universe{
+		Generation: Generation{Method: GenerationRoll, Base: 20, Dice: "2d10", Rerolls: 1},
+	}
+	feral := Background{Name: "Feral World", Generation: &Generation{Base: 25, Bonus: []string{"STR"}}}
+	hive := Background{Name: "Hive World"}
+
+	cases := []struct {
+		in  []Background
+		out Generation
+	}{
+		{in: nil, out: universe.Generation},
+		{in: []Background{hive}, out: universe.Generation},
+		{in: []Background{hive, feral}, out: Generation{Method: GenerationRoll, Base: 25, Dice: "2d10", Bonus: []string{"STR"}, Rerolls: 1}},
+	}
+
+	for i, c := range cases {
+		out := universe.GenerationOf(c.in)
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected generation on case %d: %v", i+1, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Generation_Validate(t *testing.T) {
+	block := func(values ...string) Characteristics {
+		out := Characteristics{}
+		for i, v := range values {
+			out = append(out, Upgrade{Mark: MarkSpecial, Name: v, Line: i + 1})
+		}
+		return out
+	}
+
+	cases := []struct {
+		generation Generation
+		in         Characteristics
+		warnings   int
+		msg        string
+	}{
+		{generation: Generation{}, in: block("WS 70"), warnings: 0},
+		{generation: Generation{Method: GenerationRoll, Base: 20, Dice: "2d10"}, in: block("WS 40", "BS 22"), warnings: 0},
+		{generation: Generation{Method: GenerationRoll, Base: 20, Dice: "2d10"}, in: block("WS 70", "BS 21"), warnings: 2},
+		{generation: Generation{Method: GenerationPoints, Base: 25, Points: 10, Max: 8}, in: block("WS 33", "BS 27"), warnings: 0},
+		{generation: Generation{Method: GenerationPoints, Base: 25, Points: 10, Max: 8}, in: block("WS 34", "BS 25"), warnings: 1},
+		{generation: Generation{Method: GenerationPoints, Base: 25, Points: 10, Max: 8}, in: block("WS 33", "BS 33"), warnings: 1},
+		{generation: Generation{Method: GenerationRoll, Base: 20, Dice: "2d10"}, in: block("Weapon Skill 70"), warnings: 1, msg: "line 1: the characteristic Weapon Skill can't be 70 at creation, only between 22 and 40"},
+	}
+
+	for i, c := range cases {
+		out := c.generation.Validate(c.in)
+		if len(out) != c.warnings || (len(c.msg) != 0 && out[0].Error() != c.msg) {
+			t.Logf("Unexpected warnings on case %d: %v", i+1, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Universe_CheckGenerations(t *testing.T) {
+	cases := []struct {
+		universe Universe
+		err      bool
+	}{
+		{
+			universe: Universe{
+				Generation:  Generation{Method: GenerationRoll, Base: 20, Dice: "2d10"},
+				Backgrounds: map[string][]Background{"homeworld": {{Name: "Feral World", Generation: &Generation{Base: 25}}}},
+			},
+		},
+		{
+			universe: Universe{
+				Backgrounds: map[string][]Background{"homeworld": {{Name: "Feral World", Generation: &Generation{Method: GenerationRoll, Base: 25}}}},
+			},
+			err: true,
+		},
+	}
+
+	for i, c := range cases {
+		err := c.universe.CheckGenerations()
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+		}
+	}
+}
@@ -25,25 +25,25 @@ func parseHeader(block []line) (Header, error) {
 		// Parse the field as a key and value.
 		fields := strings.Split(line.Text, ":")
 		if len(fields) != 2 {
-			return Header{}, NewError(InvalidHeaderLine, line.Number)
+			return Header{}, NewError(InvalidHeaderLine, line.Position())
 		}
 		key := strings.ToLower(strings.TrimSpace(strings.ToLower(fields[0])))
 		value := strings.TrimSpace(fields[1])
 
 		// Check key is not empty
 		if len(key) == 0 {
-			return Header{}, NewError(EmptyHeaderKey, line.Number)
+			return Header{}, NewError(EmptyHeaderKey, line.Position())
 		}
 
 		// Check value is not empty
 		if len(value) == 0 {
-			return Header{}, NewError(EmptyHeaderValue, line.Number)
+			return Header{}, NewError(EmptyHeaderValue, line.Position())
 		}
 
 		// Check the meta is unique.
 		_, found := metas[key]
 		if found {
-			return Header{}, NewError(DuplicateHeaderLine, line.Number, key)
+			return Header{}, NewError(DuplicateHeaderLine, line.Locate(strings.TrimSpace(fields[0])), key)
 		}
 
 		// Retrieve the name.
@@ -54,9 +54,10 @@ func parseHeader(block []line) (Header, error) {
 
 		// Retrieve coma separated values.
 		metas[key] = []Meta{}
-		splits := strings.Split(value, ",")
+		splits := splitOutside(value, ',')
 		for _, s := range splits {
 			l := newLine(strings.TrimSpace(s), line.Number)
+			l.File = line.File
 			meta, err := NewMeta(l)
 			if err != nil {
 				return Header{}, err
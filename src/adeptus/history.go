@@ -0,0 +1,139 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// Record is an upgrade of the character's history, along with what it did to
+// the character.
+type Record struct {
+	Upgrade
+
+	// Type is the type of the upgraded trait, as used by the cost matrix.
+	Type string
+
+	// Tier is the tier reached by the upgraded trait, or its value for the
+	// talents.
+	Tier int
+
+	// Explicit is true if the cost was given by the sheet rather than
+	// computed from the universe.
+	Explicit bool
+
+	// Computed is the cost computed from the universe when the upgrade was
+	// applied, or nil if it can't be computed.
+	Computed *int
+
+	// Background is the name of the background providing the upgrade, if
+	// any.
+	Background string
+
+	// Elite is the name of the elite advance granting the upgrade, if any.
+	Elite string
+
+	// Substitute is the name of the duplicate aptitude the upgrade replaces,
+	// if any.
+	Substitute string
+
+	// Threshold is the threshold of a gauge granting the upgrade, if any.
+	Threshold string
+
+	// Crossings are the thresholds of the gauge crossed by the upgrade.
+	Crossings []Crossing
+
+	// Expired is true if the upgrade is the removal of an expired condition.
+	Expired bool
+
+	// Refunds is the purchase refunded by the upgrade, if any.
+	Refunds *Upgrade
+}
+
+// NewRecord returns the record of the given upgrade, once applied on the
+// character by the coster.
+func NewRecord(upgrade Upgrade, coster Coster, character Character) Record {
+	record := Record{
+		Upgrade: upgrade,
+	}
+
+	switch coster := coster.(type) {
+	case Characteristic:
+		record.Type = "characteristic"
+		record.Tier = character.Characteristics[coster.Name].Tier
+	case Skill:
+		record.Type = "skill"
+		record.Tier = character.Skills[coster.FullName()].Tier
+	case Talent:
+		record.Type = "talent"
+		record.Tier = character.Talents[coster.FullName()].Value
+	case Aptitude:
+		record.Type = "aptitude"
+	case Gauge:
+		record.Type = "gauge"
+	case Spell:
+		record.Type = "spell"
+	case Rule:
+		record.Type = "rule"
+	case Elite:
+		record.Type = "elite"
+	case Condition:
+		record.Type = "condition"
+	}
+
+	return record
+}
+
+// Filter selects the records of the history to display. The zero value
+// selects every record.
+type Filter struct {
+	Types []string
+	From  time.Time
+	To    time.Time
+	Name  string
+}
+
+// IsZero returns true if the filter selects every record.
+func (f Filter) IsZero() bool {
+	return len(f.Types) == 0 && f.From.IsZero() && f.To.IsZero() && len(f.Name) == 0
+}
+
+// MatchSession returns true if the session is in the date range of the
+// filter.
+func (f Filter) MatchSession(session Session) bool {
+	if !f.From.IsZero() && session.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && session.Date.After(f.To) {
+		return false
+	}
+	return true
+}
+
+// MatchBackgrounds returns true if the backgrounds, preceding every session,
+// are in the date range of the filter.
+func (f Filter) MatchBackgrounds() bool {
+	return f.From.IsZero()
+}
+
+// MatchRecord returns true if the record is of one of the types of the filter
+// and its name contains the name of the filter.
+func (f Filter) MatchRecord(record Record) bool {
+	if len(f.Types) != 0 {
+		found := false
+		for _, typ := range f.Types {
+			if strings.EqualFold(typ, record.Type) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Name) != 0 && !strings.Contains(strings.ToLower(record.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+
+	return true
+}
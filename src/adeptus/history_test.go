@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Filter_MatchRecord(t *testing.T) {
+	record := Record{
+		Upgrade: Upgrade{Mark: MarkApply, Name: "Common Lore: Imperium"},
+		Type:    "skill",
+		Tier:    1,
+	}
+
+	cases := []struct {
+		filter Filter
+		out    bool
+	}{
+		{filter: Filter{}, out: true},
+		{filter: Filter{Types: []string{"talent", "Skill"}}, out: true},
+		{filter: Filter{Types: []string{"talent"}}, out: false},
+		{filter: Filter{Name: "lore"}, out: true},
+		{filter: Filter{Name: "awareness"}, out: false},
+		{filter: Filter{Types: []string{"skill"}, Name: "awareness"}, out: false},
+	}
+
+	for i, c := range cases {
+		out := c.filter.MatchRecord(record)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Filter_MatchSession(t *testing.T) {
+	first := time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2015, time.July, 8, 0, 0, 0, 0, time.UTC)
+	session := Session{Date: first}
+
+	cases := []struct {
+		filter Filter
+		out    bool
+	}{
+		{filter: Filter{}, out: true},
+		{filter: Filter{From: first, To: first}, out: true},
+		{filter: Filter{From: second}, out: false},
+		{filter: Filter{To: first.AddDate(0, 0, -1)}, out: false},
+	}
+
+	for i, c := range cases {
+		out := c.filter.MatchSession(session)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
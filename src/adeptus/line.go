@@ -1,6 +1,10 @@
 package main
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
 
 // comments holds the line separators describing a comment.
 var comments = [2]string{
@@ -9,10 +13,35 @@ var comments = [2]string{
 }
 
 type line struct {
+	File   string
 	Number int
 	Text   string
 }
 
+// Position locates a line, or a span of characters of a line, in the sheet
+// files. The column starts at 1, a zero column denoting the whole line.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+	Span   int
+}
+
+// String returns the position as file:line:column, or as line number if the
+// file is unknown.
+func (p Position) String() string {
+	if len(p.File) == 0 {
+		return fmt.Sprintf("line %d", p.Line)
+	}
+	if p.Line == 0 {
+		return p.File
+	}
+	if p.Column == 0 {
+		return fmt.Sprintf("%s:%d", p.File, p.Line)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
 // newLine return a wrapper for the line string
 func newLine(text string, number int) line {
 	return line{
@@ -21,6 +50,29 @@ func newLine(text string, number int) line {
 	}
 }
 
+// Position returns the position of the line.
+func (l line) Position() Position {
+	return Position{
+		File: l.File,
+		Line: l.Number,
+	}
+}
+
+// Locate returns the position of the first occurrence of the given text in
+// the line, or the position of the whole line if it doesn't contain it.
+func (l line) Locate(text string) Position {
+	p := l.Position()
+
+	i := strings.Index(l.Text, text)
+	if i == -1 || len(text) == 0 {
+		return p
+	}
+
+	p.Column = utf8.RuneCountInString(l.Text[:i]) + 1
+	p.Span = utf8.RuneCountInString(text)
+	return p
+}
+
 // Instruction returns the usefull (non-commented) part of the line.
 func (l line) Instruction() string {
 	for _, marker := range comments {
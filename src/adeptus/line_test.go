@@ -109,3 +109,75 @@ func Test_line_Instruction(t *testing.T) {
 		}
 	}
 }
+
+func Test_Position_String(t *testing.T) {
+	cases := []struct {
+		in  Position
+		out string
+	}{
+		{
+			in:  Position{Line: 3},
+			out: "line 3",
+		},
+		{
+			in:  Position{File: "sephiam.sheet", Line: 3},
+			out: "sephiam.sheet:3",
+		},
+		{
+			in:  Position{File: "sephiam.sheet", Line: 3, Column: 5, Span: 2},
+			out: "sephiam.sheet:3:5",
+		},
+		{
+			in:  Position{File: "universe.yaml"},
+			out: "universe.yaml",
+		},
+	}
+
+	for i, c := range cases {
+		out := c.in.String()
+
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_line_Locate(t *testing.T) {
+	cases := []struct {
+		in   string
+		text string
+		out  Position
+	}{
+		{
+			in:   "	+ Awareness [250]",
+			text: "[250]",
+			out:  Position{File: "sephiam.sheet", Line: 1, Column: 14, Span: 5},
+		},
+		{
+			in:   "	+ Awareness [250]",
+			text: "[300]",
+			out:  Position{File: "sephiam.sheet", Line: 1},
+		},
+		{
+			in:   "	+ Précision [250]",
+			text: "[250]",
+			out:  Position{File: "sephiam.sheet", Line: 1, Column: 14, Span: 5},
+		},
+	}
+
+	for i, c := range cases {
+		l := newLine(c.in, 1)
+		l.File = "sephiam.sheet"
+		out := l.Locate(c.text)
+
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
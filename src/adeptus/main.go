@@ -30,14 +30,45 @@ func main() {
 			Usage: "The dir location that contains the universe files.",
 			Value: ".",
 		},
+		cli.StringFlag{
+			Name:  "campaign, c",
+			Usage: "The campaign log holding the sessions shared by the character sheets.",
+		},
+		cli.BoolFlag{
+			Name:  "lenient",
+			Usage: "Turn the upgrades undefined in the universe into special rules instead of errors.",
+		},
+		cli.BoolFlag{
+			Name:  "warn-rules",
+			Usage: "Warn about each upgrade turned into a special rule because it is undefined in the universe.",
+		},
+		cli.BoolFlag{
+			Name:  "allow-debt",
+			Usage: "Warn about the upgrades spending more experience than earned instead of failing.",
+		},
+		cli.StringFlag{
+			Name:  "template",
+			Usage: "Display the character sheet with the given text template file instead.",
+		},
+		cli.BoolFlag{
+			Name:  "refund",
+			Usage: "Refund the earlier purchase reverted by each upgrade marked with -.",
+		},
 	}
 
 	app.Action = func(ctx *cli.Context) {
-		_, c, err := Bootstrap(ctx)
+		u, c, err := Bootstrap(ctx)
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
+		if len(ctx.GlobalString("template")) != 0 {
+			err = ExecuteTemplate(ctx.GlobalString("template"), u, c.View())
+			if err != nil {
+				fmt.Println(err)
+			}
+			return
+		}
 		c.Print()
 	}
 
@@ -45,13 +76,200 @@ func main() {
 		{
 			Name:  "history",
 			Usage: "display the history of a character sheet",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "type,t",
+					Usage: "display only the upgrades of the given type (characteristic, skill, talent, aptitude, gauge, spell or rule)",
+				},
+				cli.StringFlag{
+					Name:  "from",
+					Usage: "display only the sessions played since the given date",
+				},
+				cli.StringFlag{
+					Name:  "to",
+					Usage: "display only the sessions played until the given date",
+				},
+				cli.StringFlag{
+					Name:  "name,n",
+					Usage: "display only the upgrades whose name contains the given text",
+				},
+				cli.StringFlag{
+					Name:  "template",
+					Usage: "display the history with the given text template file instead",
+				},
+			},
+			Action: func(ctx *cli.Context) {
+				filter, err := HistoryFilter(ctx)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				u, c, err := Bootstrap(ctx)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				if len(ctx.String("template")) != 0 {
+					err = ExecuteTemplate(ctx.String("template"), u, c.View().Filter(filter))
+					if err != nil {
+						fmt.Println(err)
+					}
+					return
+				}
+				c.PrintHistory(filter)
+			},
+		},
+		{
+			Name:      "new",
+			Usage:     "create a new character sheet, asking the choices not given by the flags",
+			ArgsUsage: "<sheet>",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "name",
+					Usage: "the name of the character",
+				},
+				cli.StringSliceFlag{
+					Name:  "background",
+					Usage: "the background of a type, as in the header of the sheet, like \"role=Seeker (Agility)\"",
+				},
+				cli.StringFlag{
+					Name:  "career",
+					Usage: "the career of the character",
+				},
+				cli.StringSliceFlag{
+					Name:  "characteristic",
+					Usage: "the value of a characteristic, like \"WS=35\"",
+				},
+				cli.IntFlag{
+					Name:  "xp",
+					Usage: "the starting experience",
+				},
+				cli.StringFlag{
+					Name:  "date",
+					Usage: "the date of the creation session, today by default",
+				},
+				cli.Int64Flag{
+					Name:  "seed",
+					Usage: "the seed of the characteristics rolls",
+				},
+				cli.BoolFlag{
+					Name:  "batch,b",
+					Usage: "never ask, using the default choices and failing on the missing ones",
+				},
+			},
+			Action: func(ctx *cli.Context) {
+				err := CreateSheet(ctx)
+				if err != nil {
+					fmt.Println(err)
+				}
+			},
+		},
+		{
+			Name:  "roll-characteristics",
+			Usage: "roll the characteristics of a new character",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "background",
+					Usage: "the background of a type, like \"homeworld=Hive World\", whose generation method is used",
+				},
+				cli.StringSliceFlag{
+					Name:  "reroll,r",
+					Usage: "the characteristic to roll again, within the rerolls allowed",
+				},
+				cli.Int64Flag{
+					Name:  "seed",
+					Usage: "the seed of the rolls",
+				},
+			},
+			Action: func(ctx *cli.Context) {
+				err := RollCharacteristics(ctx)
+				if err != nil {
+					fmt.Println(err)
+				}
+			},
+		},
+		{
+			Name:      "export",
+			Usage:     "export a character sheet to another format, like an HTML sheet to print",
+			ArgsUsage: "<sheet>",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format,f",
+					Value: ExportHTML,
+					Usage: "the format of the export: html, markdown or vtt",
+				},
+				cli.StringFlag{
+					Name:  "system",
+					Usage: "the VTT system of the vtt export, whose mapping is in the vtt directory of the universe",
+				},
+				cli.StringFlag{
+					Name:  "output,o",
+					Usage: "the exported file, next to the sheet by default, or - for the standard output",
+				},
+				cli.BoolFlag{
+					Name:  "force",
+					Usage: "overwrite the existing exported file next to the sheet",
+				},
+			},
+			Action: func(ctx *cli.Context) {
+				err := Export(ctx)
+				if err != nil {
+					fmt.Println(err)
+				}
+			},
+		},
+		{
+			Name:  "audit",
+			Usage: "compare the explicit costs of a character sheet with the costs computed from the universe",
 			Action: func(ctx *cli.Context) {
 				_, c, err := Bootstrap(ctx)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
-				c.PrintHistory()
+				c.PrintAudit()
+			},
+		},
+		{
+			Name:  "reprice",
+			Usage: "compare the costs of a character sheet under another universe",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "universe,u",
+					Usage: "the dir location that contains the other universe files",
+				},
+				cli.BoolFlag{
+					Name:  "ignore-explicit,i",
+					Usage: "compute the explicit costs again from the other universe",
+				},
+			},
+			Action: func(ctx *cli.Context) {
+				if len(ctx.String("universe")) == 0 {
+					fmt.Printf("%s undefined universe\n", theme.Error("unable to open universe:"))
+					return
+				}
+				other, err := LoadUniverse(ctx.String("universe"))
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				u, sheet, err := BootstrapSheet(ctx)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				c, err := NewCharacter(u, sheet, CompileOptions(ctx))
+				if err != nil {
+					fmt.Printf("%s %s\n", theme.Error("unable to create character:"), Render(err))
+					return
+				}
+				PrintWarnings(c.Warnings)
+				repricings, repriced, err := Reprice(c, other, sheet, CompileOptions(ctx), !ctx.Bool("ignore-explicit"))
+				if err != nil {
+					fmt.Printf("%s %s\n", theme.Error("unable to reprice character:"), Render(err))
+					return
+				}
+				PrintReprice(c, repriced, repricings)
 			},
 		},
 		{
@@ -70,6 +288,10 @@ func main() {
 					Name:  "with-spells,s",
 					Usage: "display spells along with other upgrades",
 				},
+				cli.StringFlag{
+					Name:  "template",
+					Usage: "display the suggestions with the given text template file instead",
+				},
 			},
 			Action: func(ctx *cli.Context) {
 				u, c, err := Bootstrap(ctx)
@@ -77,9 +299,31 @@ func main() {
 					fmt.Println(err)
 					return
 				}
+				if len(ctx.String("template")) != 0 {
+					v := c.View()
+					v.Suggestions = c.Suggestions(u, ctx.Int("max"), ctx.Bool("all"), ctx.Bool("with-spells"))
+					err = ExecuteTemplate(ctx.String("template"), u, v)
+					if err != nil {
+						fmt.Println(err)
+					}
+					return
+				}
 				c.Suggest(u, ctx.Int("max"), ctx.Bool("all"), ctx.Bool("with-spells"))
 			},
 		},
+		{
+			Name:      "party",
+			Usage:     "display an overview of a party of characters",
+			ArgsUsage: "sheet...",
+			Action: func(ctx *cli.Context) {
+				u, p, err := BootstrapParty(ctx)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				p.Print(u)
+			},
+		},
 	}
 
 	err := app.Run(os.Args)
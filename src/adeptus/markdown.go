@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// ExportMarkdown is the format of the Markdown sheets, meant for the campaign
+// wikis.
+const ExportMarkdown = "markdown"
+
+// MarkdownTemplate is the default template of the Markdown sheet. Each
+// section, and each session of the history, is preceded by an anchor which
+// doesn't change from one export to the next. The universe replaces it with
+// a sheet.md file in its templates directory.
+const MarkdownTemplate = `# {{.Name}}
+
+<a id="summary"></a>
+## Summary
+
+| | |
+|---|---|
+{{- range .Backgrounds}}
+| {{cell (title .Type)}} | {{cell (title .Name)}} |
+{{- end}}
+{{- if .Career}}
+| Career | {{cell .Career}}{{if .Rank}} ({{cell .Rank}}){{end}} |
+{{- end}}
+{{- if .Alignment}}
+| Alignment | {{cell .Alignment}} |
+{{- end}}
+| Aptitudes | {{range $i, $a := .Aptitudes}}{{if $i}}, {{end}}{{cell (title (printf "%s" $a))}}{{end}} |
+| Experience | {{.Spent}}/{{.Experience}} ({{.Remaining}} remaining) |
+
+<a id="characteristics"></a>
+## Characteristics
+
+| Characteristic | Value | Advances | Bonus |
+|---|---:|---:|---:|
+{{- range .Characteristics}}
+| {{.Name}} | {{.Value}}{{if ne .Effective .Value}} ({{.Effective}}){{end}} | {{.Tier}} | {{.Bonus}} |
+{{- end}}
+{{- if .Gauges}}
+
+<a id="gauges"></a>
+## Gauges
+
+| Gauge | Value | Current | Details |
+|---|---:|---:|---|
+{{- range .Gauges}}
+| {{cell .Name}} | {{.Value}} | {{.Effective}} | {{range $i, $d := .Degradations}}{{if $i}}, {{end}}{{cell $d}}{{end}}{{if .Modifier}}{{if .Degradations}}, {{end}}conditions {{printf "%+d" .Modifier}}{{end}} |
+{{- end}}
+{{- end}}
+{{- if .Skills}}
+
+<a id="skills"></a>
+## Skills
+
+| Skill | Training | Bonus |
+|---|---|---:|
+{{- range .Skills}}
+| {{cell (title .FullName)}} | {{.Training}} | {{printf "%+d" .Effective}} |
+{{- end}}
+{{- end}}
+{{- if .Talents}}
+
+<a id="talents"></a>
+## Talents
+
+| Talent | Description |
+|---|---|
+{{- range .Talents}}
+| {{cell (title .FullName)}}{{if ne .Value 1}} ({{.Value}}){{end}} | {{cell .Description}} |
+{{- end}}
+{{- end}}
+{{- if .Spells}}
+
+<a id="spells"></a>
+## Spells
+{{- range .Spells}}
+
+### {{title .Name}}
+
+{{.Description}}
+{{- if .Attributes}}
+{{range $name, $value := .Attributes}}
+- **{{title $name}}**: {{$value}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .Elites}}
+
+<a id="elite-advances"></a>
+## Elite advances
+
+| Elite advance | Description |
+|---|---|
+{{- range .Elites}}
+| {{cell (title .Name)}} | {{cell .Description}} |
+{{- end}}
+{{- end}}
+{{- if .Conditions}}
+
+<a id="conditions"></a>
+## Conditions
+
+| Condition | Expiry | Description |
+|---|---|---|
+{{- range .Conditions}}
+| {{cell (title .Condition.Name)}} | {{.}} | {{cell .Condition.Description}} |
+{{- end}}
+{{- end}}
+{{- if .Rules}}
+
+<a id="rules"></a>
+## Rules
+
+| Rule | Description |
+|---|---|
+{{- range .Rules}}
+| {{cell (title .Name)}} | {{cell .Description}} |
+{{- end}}
+{{- end}}
+{{- if .Modifiers}}
+
+<a id="modifiers"></a>
+## Modifiers
+
+| Target | Modifier | Source |
+|---|---|---|
+{{- range .Modifiers}}
+| {{cell (title .Modifier.Target)}} | {{cell (printf "%s" .Modifier)}} | {{.Type}} {{cell (title .Name)}} |
+{{- end}}
+{{- end}}
+
+<a id="history"></a>
+## History
+{{- range $i, $balance := .Balances}}
+
+<a id="{{$.SessionAnchor $i}}"></a>
+### {{.Session.Date.Format "2006/01/02"}} {{.Session.Title}}
+
+Earned {{.Earned}}, spent {{.Spent}}, {{.Remaining}} remaining.
+{{- if .Upgrades}}
+
+| | Upgrade | Type | Cost |
+|---|---|---|---:|
+{{- range .Upgrades}}
+| {{cell .Mark}} | {{cell (title .Name)}} | {{.Type}} | {{with .Cost}}{{.}}{{end}} |
+{{- end}}
+{{- end}}
+{{- end}}
+`
+
+// markdownFuncs returns the functions available to the Markdown templates,
+// which are the functions of every template along with:
+// - anchor joins its words into an identifier, like session-2015-07-01-creation
+// - cell escapes a text for a table cell
+func markdownFuncs(universe Universe) map[string]interface{} {
+	funcs := templateFuncs(universe)
+	funcs["anchor"] = anchor
+	funcs["cell"] = cell
+	return funcs
+}
+
+// anchor returns the identifier made of the words of the given texts, in
+// lower case and separated by dashes.
+func anchor(texts ...string) string {
+	words := strings.FieldsFunc(strings.ToLower(strings.Join(texts, " ")), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+// SessionAnchor returns the anchor of the session of the ith balance, like
+// session-2015-07-01-creation. The sessions sharing the date and title of an
+// earlier one are told apart by their rank, like session-2015-07-01-2.
+func (v View) SessionAnchor(i int) string {
+	id := func(b Balance) string {
+		return anchor("session", b.Session.Date.Format("2006-01-02"), b.Session.Title)
+	}
+
+	rank := 1
+	for _, b := range v.Balances[:i] {
+		if id(b) == id(v.Balances[i]) {
+			rank++
+		}
+	}
+	if rank == 1 {
+		return id(v.Balances[i])
+	}
+	return fmt.Sprintf("%s-%d", id(v.Balances[i]), rank)
+}
+
+// cell escapes the pipes and line breaks of a text, which would end a table
+// cell or row.
+func cell(text string) string {
+	text = strings.Replace(text, "|", `\|`, -1)
+	return strings.Replace(strings.TrimSpace(text), "\n", "<br>", -1)
+}
+
+// RenderMarkdown writes the Markdown sheet of the character, using the
+// template of the universe directory if any.
+func RenderMarkdown(w io.Writer, c *Character, universe Universe, dir string) error {
+	raw, err := readTemplate(dir, "sheet.md", MarkdownTemplate)
+	if err != nil {
+		return err
+	}
+	t, err := template.New("sheet.md").Funcs(markdownFuncs(universe)).Parse(raw)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, c.View())
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_RenderMarkdown(t *testing.T) {
+	universe, c := exportedCharacter(t)
+	c.Spells = map[string]Spell{
+		"Smite": {Name: "Smite", Description: "Lightning | fire", Attributes: map[string]interface{}{"range": "10m", "focus": "WP"}},
+	}
+	c.Balances[0].Session.Title = "The Creation"
+	c.Rules = map[string]Rule{"Brute": {Name: "Brute", Modifiers: []Modifier{{Target: "WS", Value: 10}}}}
+	c.Balances = append(c.Balances, Balance{}, Balance{})
+
+	var out bytes.Buffer
+	err := RenderMarkdown(&out, c, universe, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"# Tom & Jerry\n",
+		"<a id=\"characteristics\"></a>\n## Characteristics\n",
+		"| WS | 35 (45) | 1 | 4 |\n",
+		"| Awareness | Trained | +10 |\n",
+		"### Smite\n\nLightning | fire\n\n- **Focus**: WP\n- **Range**: 10m\n",
+		"<a id=\"session-0001-01-01-the-creation\"></a>\n### 0001/01/01 The Creation\n",
+		"| + | WS +5 | characteristic | 100 |\n",
+		"<a id=\"modifiers\"></a>\n## Modifiers\n",
+		"| WS | +10 | rule Brute |\n",
+		"<a id=\"session-0001-01-01\"></a>\n",
+		"<a id=\"session-0001-01-01-2\"></a>\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Logf("Missing %q in the sheet:", expected)
+			t.Logf("%s", out.String())
+			t.Fail()
+		}
+	}
+}
+
+func Test_cell(t *testing.T) {
+	cases := []struct {
+		in  string
+		out string
+	}{
+		{in: "Strong", out: "Strong"},
+		{in: "a | b", out: `a \| b`},
+		{in: "first\nsecond\n", out: "first<br>second"},
+	}
+
+	for i, c := range cases {
+		out := cell(c.in)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d: expected %q, having %q", i+1, c.out, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_anchor(t *testing.T) {
+	cases := []struct {
+		in  []string
+		out string
+	}{
+		{in: []string{"Elite advances"}, out: "elite-advances"},
+		{in: []string{"session", "2015-07-01", "First scenario!"}, out: "session-2015-07-01-first-scenario"},
+	}
+
+	for i, c := range cases {
+		out := anchor(c.in...)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d: expected %q, having %q", i+1, c.out, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_View_SessionAnchor(t *testing.T) {
+	date := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	v := View{
+		Balances: []Balance{
+			{Session: Session{Date: date}},
+			{Session: Session{Date: date, Title: "Creation"}},
+			{Session: Session{Date: date}},
+			{Session: Session{Date: date}},
+		},
+	}
+
+	expected := []string{"session-2026-01-01", "session-2026-01-01-creation", "session-2026-01-01-2", "session-2026-01-01-3"}
+	for i, e := range expected {
+		out := v.SessionAnchor(i)
+		if out != e {
+			t.Logf("Unexpected output on case %d: expected %q, having %q", i+1, e, out)
+			t.Fail()
+		}
+	}
+}
@@ -9,18 +9,67 @@ type Meta struct {
 	Label   string   `yaml:"label"`
 	Options []string `yaml:"options"`
 	Line    int      `yaml:"-"`
+	File    string   `yaml:"-"`
 }
 
 // NewMeta returns a meta with name and options given the label.
 func NewMeta(l line) (Meta, error) {
 
-	// NOTE: the options are not yet supported. Return an error.
-	if strings.Contains(l.Text, "(") {
-		return Meta{}, NewError(InvalidHeaderOptions, l.Number)
+	// The options follow the label, between parenthesis.
+	i := strings.Index(l.Text, "(")
+	if i == -1 {
+		if strings.Contains(l.Text, ")") {
+			return Meta{}, NewError(InvalidHeaderOptions, l.Locate(")"))
+		}
+
+		return Meta{
+			Label: l.Text,
+			Line:  l.Number,
+			File:  l.File,
+		}, nil
+	}
+
+	// The options must close the meta, their parenthesis being balanced.
+	raw := strings.TrimSpace(l.Text[i:])
+	depth := 0
+	for j, r := range raw {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 && j != len(raw)-1 {
+			return Meta{}, NewError(InvalidHeaderOptions, l.Locate(raw))
+		}
+	}
+	if depth != 0 {
+		return Meta{}, NewError(InvalidHeaderOptions, l.Locate(raw))
+	}
+
+	// Split the coma separated options, each one possibly holding
+	// parenthesis.
+	options := []string{}
+	for _, option := range splitOutside(raw[1:len(raw)-1], ',') {
+		option = strings.TrimSpace(option)
+		if len(option) == 0 {
+			return Meta{}, NewError(InvalidHeaderOptions, l.Locate(raw))
+		}
+		options = append(options, option)
 	}
 
 	return Meta{
-		Label: l.Text,
-		Line:  l.Number,
+		Label:   strings.TrimSpace(l.Text[:i]),
+		Options: options,
+		Line:    l.Number,
+		File:    l.File,
 	}, nil
 }
+
+// Position returns the position of the meta in the sheet.
+func (m Meta) Position() Position {
+	return Position{
+		File: m.File,
+		Line: m.Line,
+	}
+}
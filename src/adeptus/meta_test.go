@@ -23,6 +23,42 @@ func Test_NewMeta(t *testing.T) {
 			},
 			err: false,
 		},
+		{
+			in: "Hive World (Agility)",
+			out: Meta{
+				Label:   "Hive World",
+				Options: []string{"Agility"},
+			},
+			err: false,
+		},
+		{
+			in: "Seeker (Common Lore (Imperium, Tech), Weapon Skill )",
+			out: Meta{
+				Label:   "Seeker",
+				Options: []string{"Common Lore (Imperium, Tech)", "Weapon Skill"},
+			},
+			err: false,
+		},
+		{
+			in:  "Seeker (Agility",
+			out: Meta{},
+			err: true,
+		},
+		{
+			in:  "Seeker (Agility) Fellowship",
+			out: Meta{},
+			err: true,
+		},
+		{
+			in:  "Seeker (Agility,)",
+			out: Meta{},
+			err: true,
+		},
+		{
+			in:  "Seeker Agility)",
+			out: Meta{},
+			err: true,
+		},
 	}
 
 	for i, c := range cases {
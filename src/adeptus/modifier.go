@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bradfitz/slice"
+)
+
+// Modifier is a change of the value of a characteristic, skill or gauge,
+// given by a talent, a rule or a condition.
+type Modifier struct {
+	Target string `yaml:"target"`
+
+	// Value is added to the value of the target, or to its tests for the
+	// skills.
+	Value int `yaml:"value"`
+
+	// Bonus is added to the bonus of the target characteristic.
+	Bonus int `yaml:"bonus"`
+
+	// Multiply multiplies the bonus of the target characteristic.
+	Multiply int `yaml:"multiply"`
+
+	// Tag restricts the modifier to the situation it describes, like the
+	// tests of a skill against fear. The tagged modifiers are displayed but
+	// don't change the effective value of the target.
+	Tag string `yaml:"tag"`
+
+	// Stacks multiplies the modifier by the value of the stackable talent
+	// giving it.
+	Stacks bool `yaml:"stacks"`
+}
+
+// String returns the effect of the modifier.
+func (m Modifier) String() string {
+	effects := []string{}
+	if m.Value != 0 {
+		effects = append(effects, fmt.Sprintf("%+d", m.Value))
+	}
+	if m.Bonus != 0 {
+		effects = append(effects, fmt.Sprintf("bonus %+d", m.Bonus))
+	}
+	if m.Multiply != 0 {
+		effects = append(effects, fmt.Sprintf("bonus x%d", m.Multiply))
+	}
+
+	out := strings.Join(effects, ", ")
+	if len(m.Tag) != 0 {
+		out = fmt.Sprintf("%s %s", out, m.Tag)
+	}
+	return out
+}
+
+// scale returns the modifier multiplied by the given value if it stacks.
+func (m Modifier) scale(value int) Modifier {
+	if !m.Stacks {
+		return m
+	}
+	m.Value *= value
+	m.Bonus *= value
+	m.Multiply *= value
+	return m
+}
+
+// Source is a modifier along with the talent, rule or condition giving it.
+type Source struct {
+	Type     string
+	Name     string
+	Modifier Modifier
+}
+
+// Sources returns the modifiers of the character's talents, rules and
+// conditions, sorted by target then source.
+func (c *Character) Sources() []Source {
+	sources := []Source{}
+
+	for _, talent := range c.Talents {
+		name := talent.FullName()
+		if talent.Value != 1 {
+			name = fmt.Sprintf("%s (%d)", name, talent.Value)
+		}
+		for _, modifier := range talent.Modifiers {
+			sources = append(sources, Source{Type: "talent", Name: name, Modifier: modifier.scale(talent.Value)})
+		}
+	}
+
+	for _, rule := range c.Rules {
+		for _, modifier := range rule.Modifiers {
+			sources = append(sources, Source{Type: "rule", Name: rule.Name, Modifier: modifier})
+		}
+	}
+
+	for _, effect := range c.Conditions {
+		for _, modifier := range effect.Condition.Modifiers {
+			sources = append(sources, Source{Type: "condition", Name: effect.Condition.Name, Modifier: modifier})
+		}
+	}
+
+	slice.Sort(sources, func(i, j int) bool {
+		ti, tj := strings.ToLower(sources[i].Modifier.Target), strings.ToLower(sources[j].Modifier.Target)
+		if ti != tj {
+			return ti < tj
+		}
+		if sources[i].Type != sources[j].Type {
+			return sources[i].Type < sources[j].Type
+		}
+		if sources[i].Name != sources[j].Name {
+			return sources[i].Name < sources[j].Name
+		}
+		return sources[i].Modifier.Tag < sources[j].Modifier.Tag
+	})
+
+	return sources
+}
+
+// Modifier returns the sum of the untagged modifiers of the character's
+// characteristic, skill or gauge of the given name.
+func (c *Character) Modifier(target string) int {
+	sum := 0
+	for _, source := range c.Sources() {
+		if strings.EqualFold(source.Modifier.Target, target) && len(source.Modifier.Tag) == 0 {
+			sum += source.Modifier.Value
+		}
+	}
+	return sum
+}
+
+// Bonus returns the effective bonus of the character's characteristic: the
+// tens of its effective value, increased then multiplied by the untagged
+// modifiers.
+func (c *Character) Bonus(name string) int {
+	bonus := (c.Characteristics[name].Value + c.Modifier(name)) / 10
+	multiplier := 1
+	for _, source := range c.Sources() {
+		if !strings.EqualFold(source.Modifier.Target, name) || len(source.Modifier.Tag) != 0 {
+			continue
+		}
+		bonus += source.Modifier.Bonus
+		if source.Modifier.Multiply != 0 {
+			multiplier *= source.Modifier.Multiply
+		}
+	}
+	return bonus * multiplier
+}
+
+// CheckModifiers returns an error if a modifier of a talent, rule or
+// condition targets neither a characteristic, a skill nor a gauge of the
+// universe.
+func (u Universe) CheckModifiers() error {
+	check := func(name string, modifiers []Modifier) error {
+		for _, m := range modifiers {
+			if !u.isTarget(m.Target) {
+				return NewError(InvalidModifier, m.Target, name)
+			}
+		}
+		return nil
+	}
+
+	for _, t := range u.Talents {
+		if err := check(t.Name, t.Modifiers); err != nil {
+			return err
+		}
+	}
+	for _, r := range u.Rules {
+		if err := check(r.Name, r.Modifiers); err != nil {
+			return err
+		}
+	}
+	for _, c := range u.Conditions {
+		if err := check(c.Name, c.Modifiers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isTarget returns true if the name is the name of a characteristic, skill or
+// gauge of the universe.
+func (u Universe) isTarget(name string) bool {
+	for _, characteristic := range u.Characteristics {
+		if strings.EqualFold(characteristic.Name, name) {
+			return true
+		}
+	}
+	if _, found := u.FindSkill(Upgrade{Name: name}); found {
+		return true
+	}
+	for _, gauge := range u.Gauges {
+		if strings.EqualFold(gauge.Name, name) {
+			return true
+		}
+	}
+	return false
+}
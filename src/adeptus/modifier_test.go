@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_Character_Sources(t *testing.T) {
+	c := Character{
+		Characteristics: map[string]Characteristic{
+			"STR": {Name: "STR", Value: 35},
+			"TOU": {Name: "TOU", Value: 30},
+		},
+		Talents: map[string]Talent{
+			"Unnatural Strength": {
+				Name:  "Unnatural Strength",
+				Value: 2,
+				Modifiers: []Modifier{
+					{Target: "STR", Multiply: 1, Stacks: true},
+				},
+			},
+			"Sound Constitution": {
+				Name:  "Sound Constitution",
+				Value: 3,
+				Modifiers: []Modifier{
+					{Target: "Wounds", Value: 1, Stacks: true},
+				},
+			},
+			"Resistance": {
+				Name:      "Resistance",
+				Value:     1,
+				Modifiers: []Modifier{{Target: "WP", Value: 10, Tag: "against fear"}},
+			},
+		},
+		Rules: map[string]Rule{
+			"Brute": {Name: "Brute", Modifiers: []Modifier{{Target: "TOU", Bonus: 1}}},
+		},
+		Conditions: map[string]Effect{
+			"Fatigued": {Condition: Condition{Name: "Fatigued", Modifiers: []Modifier{{Target: "STR", Value: -10}}}},
+		},
+	}
+
+	sources := c.Sources()
+	if len(sources) != 5 || sources[0].Modifier.Target != "STR" || sources[0].Type != "condition" || sources[1].Name != "Unnatural Strength (2)" {
+		t.Logf("Unexpected sources: %v", sources)
+		t.Fail()
+	}
+
+	cases := []struct {
+		target   string
+		modifier int
+		bonus    int
+	}{
+		{target: "STR", modifier: -10, bonus: 4},
+		{target: "TOU", modifier: 0, bonus: 4},
+		{target: "Wounds", modifier: 3},
+		{target: "WP", modifier: 0},
+	}
+
+	for i, cc := range cases {
+		modifier := c.Modifier(cc.target)
+		if modifier != cc.modifier {
+			t.Logf("Unexpected modifier on case %d: expected %d, having %d", i+1, cc.modifier, modifier)
+			t.Fail()
+		}
+		if _, found := c.Characteristics[cc.target]; !found {
+			continue
+		}
+		bonus := c.Bonus(cc.target)
+		if bonus != cc.bonus {
+			t.Logf("Unexpected bonus on case %d: expected %d, having %d", i+1, cc.bonus, bonus)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Modifier_String(t *testing.T) {
+	cases := []struct {
+		in       Modifier
+		expected string
+	}{
+		{in: Modifier{Value: 10}, expected: "+10"},
+		{in: Modifier{Value: -5, Bonus: 1}, expected: "-5, bonus +1"},
+		{in: Modifier{Multiply: 2}, expected: "bonus x2"},
+		{in: Modifier{Value: 10, Tag: "against fear"}, expected: "+10 against fear"},
+	}
+
+	for i, c := range cases {
+		out := c.in.String()
+		if out != c.expected {
+			t.Logf("Unexpected string on case %d: expected %q, having %q", i+1, c.expected, out)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/slice"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Draft holds the choices made to create a character sheet.
+type Draft struct {
+	Name string
+
+	// Metas holds the background of each type, and the career, along with
+	// their options.
+	Metas map[string]Meta
+
+	// Characteristics holds the value of each characteristic.
+	Characteristics map[string]int
+
+	// XP is the starting experience, or nil if not chosen yet.
+	XP *int
+
+	// Date is the date of the creation session.
+	Date time.Time
+}
+
+// Sheet returns the text of the character sheet.
+func (d Draft) Sheet(universe Universe) string {
+	lines := []string{fmt.Sprintf("Name: %s", d.Name)}
+
+	for _, typ := range d.types(universe) {
+		meta, found := d.Metas[typ]
+		if !found {
+			continue
+		}
+		label := meta.Label
+		if len(meta.Options) != 0 {
+			label = fmt.Sprintf("%s (%s)", label, strings.Join(meta.Options, ", "))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", strings.Title(typ), label))
+	}
+
+	lines = append(lines, "")
+	for _, characteristic := range universe.Characteristics {
+		lines = append(lines, fmt.Sprintf("%s %d", characteristic.Name, d.Characteristics[characteristic.Name]))
+	}
+
+	if d.XP != nil && *d.XP != 0 {
+		lines = append(lines, "", fmt.Sprintf("%s Creation [%d]", d.Date.Format("2006/01/02"), *d.XP))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// types returns the types of background of the universe in the order of the
+// sheet, followed by the career if the universe defines careers.
+func (d Draft) types(universe Universe) []string {
+	types := []string{}
+	for typ := range universe.Backgrounds {
+		types = append(types, typ)
+	}
+	slice.Sort(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	if len(universe.Careers) != 0 {
+		types = append(types, CareerMeta)
+	}
+	return types
+}
+
+// Wizard completes a draft with the choices of the user, asked on the input.
+// A wizard without input completes the draft with the default choices, and
+// fails on the choices without default.
+type Wizard struct {
+	Universe Universe
+	Input    *bufio.Reader
+	Output   io.Writer
+	Rand     *rand.Rand
+}
+
+// Complete returns the draft completed with the missing choices.
+func (w Wizard) Complete(d Draft) (Draft, error) {
+	var err error
+
+	if d.Metas == nil {
+		d.Metas = make(map[string]Meta)
+	}
+	if d.Characteristics == nil {
+		d.Characteristics = make(map[string]int)
+	}
+
+	// Choose the name.
+	if len(d.Name) == 0 {
+		d.Name, err = w.ask("Name", "")
+		if err != nil {
+			return Draft{}, err
+		}
+	}
+
+	// Choose the background of each type, and the career.
+	types := d.types(w.Universe)
+	for typ := range d.Metas {
+		if !in(typ, types) {
+			return Draft{}, fmt.Errorf("undefined background type %s", typ)
+		}
+	}
+	for _, typ := range types {
+		names := []string{}
+		if typ == CareerMeta {
+			for _, career := range w.Universe.Careers {
+				names = append(names, career.Name)
+			}
+		} else {
+			for _, background := range w.Universe.Backgrounds[typ] {
+				names = append(names, background.Name)
+			}
+		}
+
+		if meta, found := d.Metas[typ]; found {
+			if !in(strings.ToLower(meta.Label), lower(names)) {
+				return Draft{}, fmt.Errorf("undefined %s %s", typ, meta.Label)
+			}
+			continue
+		}
+
+		label, err := w.choose(strings.Title(typ), names)
+		if err != nil {
+			return Draft{}, err
+		}
+		d.Metas[typ] = Meta{Label: label}
+	}
+
+	// Choose the replacement of the duplicate aptitudes.
+	if w.Universe.DuplicateAptitudes.Policy == PolicySubstitute {
+		err = w.substitute(&d)
+		if err != nil {
+			return Draft{}, err
+		}
+	}
+
+	// Roll, buy or choose each characteristic.
+	err = w.characteristics(&d)
+	if err != nil {
+		return Draft{}, err
+	}
+
+	// Choose the starting experience.
+	if d.XP == nil {
+		raw, err := w.ask("Starting experience", "0")
+		if err != nil {
+			return Draft{}, err
+		}
+		xp, err := strconv.Atoi(raw)
+		if err != nil {
+			return Draft{}, fmt.Errorf("invalid experience %s", raw)
+		}
+		d.XP = &xp
+	}
+
+	if d.Date.IsZero() {
+		d.Date = time.Now()
+	}
+
+	return d, nil
+}
+
+// substitute chooses the options of the backgrounds replacing the aptitudes
+// given twice, in the order the backgrounds are applied.
+func (w Wizard) substitute(d *Draft) error {
+	owned := make(map[Aptitude]struct{})
+	for _, typ := range d.types(w.Universe) {
+		meta := d.Metas[typ]
+		background, found := w.Universe.FindBackground(typ, meta.Label)
+		if !found {
+			continue
+		}
+
+		used := 0
+		for _, raw := range background.Upgrades {
+			aptitude, found := w.Universe.FindAptitude(Upgrade{Name: raw})
+			if !found {
+				continue
+			}
+			if _, ok := owned[aptitude]; !ok {
+				owned[aptitude] = struct{}{}
+				continue
+			}
+
+			// Use the options already given first.
+			if used < len(meta.Options) {
+				substitute, _ := w.Universe.FindAptitude(Upgrade{Name: meta.Options[used]})
+				owned[substitute] = struct{}{}
+				used++
+				continue
+			}
+
+			choices := []string{}
+			for _, a := range w.Universe.Aptitudes {
+				if _, ok := owned[a]; !ok && w.Universe.DuplicateAptitudes.Allows(a) {
+					choices = append(choices, string(a))
+				}
+			}
+			choice, err := w.choose(fmt.Sprintf("%s gives %s again, replacement", background.Name, aptitude), choices)
+			if err != nil {
+				return err
+			}
+
+			meta.Options = append(meta.Options, choice)
+			owned[Aptitude(choice)] = struct{}{}
+			used++
+		}
+		d.Metas[typ] = meta
+	}
+	return nil
+}
+
+// characteristics completes the characteristics of the draft according to
+// the generation method of the universe.
+func (w Wizard) characteristics(d *Draft) error {
+	// The first background defining a generation method overrides the one of
+	// the universe.
+	backgrounds := []Background{}
+	for _, typ := range d.types(w.Universe) {
+		if background, found := w.Universe.FindBackground(typ, d.Metas[typ].Label); found {
+			backgrounds = append(backgrounds, background)
+		}
+	}
+	generation := w.Universe.GenerationOf(backgrounds)
+	budget := generation.Points
+
+	// Use the names of the universe for the given characteristics.
+	given := d.Characteristics
+	d.Characteristics = make(map[string]int)
+	for name, value := range given {
+		found := false
+		for _, characteristic := range w.Universe.Characteristics {
+			if strings.EqualFold(characteristic.Name, name) {
+				d.Characteristics[characteristic.Name] = value
+				name = characteristic.Name
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("undefined characteristic %s", name)
+		}
+
+		// The given characteristics are bought with the points too.
+		if generation.Method == GenerationPoints {
+			points := value - generation.Base
+			if points < 0 || (generation.Max != 0 && points > generation.Max) {
+				return fmt.Errorf("invalid value %d for %s", value, name)
+			}
+			budget -= points
+		}
+	}
+	if budget < 0 {
+		return fmt.Errorf("the characteristics given exceed the %d points", generation.Points)
+	}
+
+	for _, characteristic := range w.Universe.Characteristics {
+		if _, found := d.Characteristics[characteristic.Name]; found {
+			continue
+		}
+
+		switch generation.Method {
+		case GenerationRoll:
+			rolled := generation.Roll(characteristic.Name, w.Rand)
+			raw, err := w.ask(characteristic.Name, strconv.Itoa(rolled.Value))
+			if err != nil {
+				return err
+			}
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("invalid value %s for %s", raw, characteristic.Name)
+			}
+			d.Characteristics[characteristic.Name] = value
+
+		case GenerationPoints:
+			raw, err := w.ask(fmt.Sprintf("%s points (%d left)", characteristic.Name, budget), "0")
+			if err != nil {
+				return err
+			}
+			points, err := strconv.Atoi(raw)
+			if err != nil || points < 0 || points > budget || (generation.Max != 0 && points > generation.Max) {
+				return fmt.Errorf("invalid points %s for %s", raw, characteristic.Name)
+			}
+			budget -= points
+			d.Characteristics[characteristic.Name] = generation.Base + points
+
+		default:
+			raw, err := w.ask(characteristic.Name, "")
+			if err != nil {
+				return err
+			}
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("invalid value %s for %s", raw, characteristic.Name)
+			}
+			d.Characteristics[characteristic.Name] = value
+		}
+	}
+
+	return nil
+}
+
+// ask returns the answer to the question, or the default answer if there is
+// no input or the answer is empty.
+func (w Wizard) ask(question string, answer string) (string, error) {
+	if w.Input == nil {
+		if len(answer) == 0 {
+			return "", fmt.Errorf("undefined %s", strings.ToLower(question))
+		}
+		return answer, nil
+	}
+
+	for {
+		if len(answer) != 0 {
+			fmt.Fprintf(w.Output, "%s [%s]: ", question, answer)
+		} else {
+			fmt.Fprintf(w.Output, "%s: ", question)
+		}
+
+		raw, err := w.Input.ReadString('\n')
+		raw = strings.TrimSpace(raw)
+		if len(raw) != 0 {
+			return raw, nil
+		}
+		if len(answer) != 0 {
+			return answer, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("undefined %s", strings.ToLower(question))
+		}
+	}
+}
+
+// choose returns the choice of the user between the given choices, by
+// number or by name.
+func (w Wizard) choose(question string, choices []string) (string, error) {
+	if len(choices) == 0 {
+		return "", fmt.Errorf("no choice for %s", strings.ToLower(question))
+	}
+	if w.Input == nil {
+		return "", fmt.Errorf("undefined %s", strings.ToLower(question))
+	}
+
+	fmt.Fprintf(w.Output, "%s\n", theme.Title(question))
+	for i, choice := range choices {
+		fmt.Fprintf(w.Output, "  %d. %s\n", i+1, choice)
+	}
+
+	for {
+		raw, err := w.ask("Choice", "")
+		if err != nil {
+			return "", fmt.Errorf("undefined %s", strings.ToLower(question))
+		}
+
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		for _, choice := range choices {
+			if strings.EqualFold(choice, raw) {
+				return choice, nil
+			}
+		}
+		fmt.Fprintf(w.Output, "%s %s\n", theme.Error("invalid choice:"), raw)
+	}
+}
+
+// lower returns the given strings in lower case.
+func lower(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
+
+// CreateSheet creates a new character sheet from the choices given on the
+// command line, asking the missing ones unless in batch mode, and writes it
+// once checked.
+func CreateSheet(ctx *cli.Context) error {
+	universe, err := LoadUniverse(ctx.GlobalString("universe"))
+	if err != nil {
+		return err
+	}
+
+	args := ctx.Args()
+	if len(args) != 1 {
+		return fmt.Errorf("%s expected the path of the character sheet", theme.Error("unable to create character sheet:"))
+	}
+	path := args[0]
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s the file %s already exists", theme.Error("unable to create character sheet:"), path)
+	}
+
+	draft, err := NewDraft(ctx)
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("unable to create character sheet:"), err)
+	}
+
+	seed := time.Now().UnixNano()
+	if ctx.IsSet("seed") {
+		seed = ctx.Int64("seed")
+	}
+	wizard := Wizard{
+		Universe: universe,
+		Output:   os.Stdout,
+		Rand:     rand.New(rand.NewSource(seed)),
+	}
+	if !ctx.Bool("batch") {
+		wizard.Input = bufio.NewReader(os.Stdin)
+	}
+
+	draft, err = wizard.Complete(draft)
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("unable to create character sheet:"), err)
+	}
+
+	// Check the sheet compiles before writing it.
+	text := draft.Sheet(universe)
+	sheet, err := parseSheet(strings.NewReader(text), path)
+	if err == nil {
+		_, err = NewCharacter(universe, sheet, CompileOptions(ctx))
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("unable to create character sheet:"), Render(err))
+	}
+
+	err = ioutil.WriteFile(path, []byte(text), 0644)
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("unable to create character sheet:"), err)
+	}
+
+	return nil
+}
+
+// NewDraft returns the draft holding the choices given on the command line.
+func NewDraft(ctx *cli.Context) (Draft, error) {
+	draft := Draft{
+		Name:            ctx.String("name"),
+		Metas:           make(map[string]Meta),
+		Characteristics: make(map[string]int),
+	}
+
+	// The backgrounds are given as in the header of the sheet.
+	for _, raw := range ctx.StringSlice("background") {
+		typ, label, ok := splitFlag(raw)
+		if !ok {
+			return Draft{}, fmt.Errorf("invalid background %s", raw)
+		}
+		meta, err := NewMeta(newLine(label, 0))
+		if err != nil {
+			return Draft{}, fmt.Errorf("invalid background %s", raw)
+		}
+		draft.Metas[strings.ToLower(typ)] = meta
+	}
+	if len(ctx.String("career")) != 0 {
+		draft.Metas[CareerMeta] = Meta{Label: ctx.String("career")}
+	}
+
+	for _, raw := range ctx.StringSlice("characteristic") {
+		name, rawValue, ok := splitFlag(raw)
+		if !ok {
+			return Draft{}, fmt.Errorf("invalid characteristic %s", raw)
+		}
+		value, err := strconv.Atoi(rawValue)
+		if err != nil {
+			return Draft{}, fmt.Errorf("invalid characteristic %s", raw)
+		}
+		draft.Characteristics[name] = value
+	}
+
+	if ctx.IsSet("xp") {
+		draft.XP = IntP(ctx.Int("xp"))
+	} else if ctx.Bool("batch") {
+		draft.XP = IntP(0)
+	}
+
+	if len(ctx.String("date")) != 0 {
+		date, err := parseDate(ctx.String("date"))
+		if err != nil {
+			return Draft{}, fmt.Errorf("invalid date %s", ctx.String("date"))
+		}
+		draft.Date = date
+	}
+
+	return draft, nil
+}
+
+// splitFlag returns the key and value of a flag given as key=value, and false
+// if either is empty.
+func splitFlag(raw string) (string, string, bool) {
+	fields := strings.SplitN(raw, "=", 2)
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	key, value := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+	return key, value, len(key) != 0 && len(value) != 0
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newWizardUniverse() Universe {
+	return Universe{
+		Aptitudes: []Aptitude{"Agility", "Perception", "Willpower"},
+		Characteristics: []Characteristic{
+			{Name: "WS"},
+			{Name: "BS"},
+		},
+		Backgrounds: map[string][]Background{
+			"homeworld": {
+				{Type: "homeworld", Name: "Hive World", Upgrades: []string{"Perception"}},
+				{Type: "homeworld", Name: "Feral World", Upgrades: []string{"Willpower"}},
+			},
+			"role": {
+				{Type: "role", Name: "Seeker", Upgrades: []string{"Perception"}},
+			},
+		},
+		DuplicateAptitudes: AptitudePolicy{Policy: PolicySubstitute},
+	}
+}
+
+func Test_Wizard_Complete_Batch(t *testing.T) {
+	universe := newWizardUniverse()
+	universe.Generation = Generation{Method: GenerationRoll, Base: 20, Dice: "2d10"}
+
+	wizard := Wizard{Universe: universe, Output: ioutil.Discard, Rand: rand.New(rand.NewSource(1))}
+
+	// The choices without default are required.
+	_, err := wizard.Complete(Draft{Name: "Test", XP: IntP(0)})
+	if err == nil {
+		t.Logf("Expected an error on the missing backgrounds")
+		t.Fail()
+	}
+
+	draft := Draft{
+		Name: "Test",
+		Metas: map[string]Meta{
+			"homeworld": {Label: "hive world"},
+			"role":      {Label: "Seeker", Options: []string{"Agility"}},
+		},
+		Characteristics: map[string]int{"ws": 35},
+		XP:              IntP(1000),
+		Date:            time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	out, err := wizard.Complete(draft)
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	if out.Characteristics["WS"] != 35 || out.Characteristics["BS"] < 22 || out.Characteristics["BS"] > 40 {
+		t.Logf("Unexpected characteristics: %v", out.Characteristics)
+		t.Fail()
+	}
+
+	// The sheet compiles.
+	sheet, err := ParseSheet(strings.NewReader(out.Sheet(universe)))
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	character, err := NewCharacter(universe, sheet, Options{})
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	if character.Experience != 1000 || len(character.Aptitudes) != 2 {
+		t.Logf("Unexpected character: %v", character)
+		t.Fail()
+	}
+}
+
+func Test_Wizard_Complete_Interactive(t *testing.T) {
+	universe := newWizardUniverse()
+	universe.Generation = Generation{Method: GenerationPoints, Base: 25, Points: 10, Max: 8}
+
+	input := strings.Join([]string{
+		"Alice",     // name
+		"1",         // homeworld
+		"seeker",    // role
+		"4",         // invalid choice
+		"Willpower", // replacement
+		"8",         // WS points
+		"",          // BS points
+		"500",       // experience
+	}, "\n") + "\n"
+
+	wizard := Wizard{
+		Universe: universe,
+		Input:    bufio.NewReader(strings.NewReader(input)),
+		Output:   ioutil.Discard,
+		Rand:     rand.New(rand.NewSource(1)),
+	}
+	out, err := wizard.Complete(Draft{Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := "Name: Alice\nHomeworld: Hive World\nRole: Seeker (Willpower)\n\nWS 33\nBS 25\n\n2020/01/01 Creation [500]\n"
+	if out.Sheet(universe) != expected {
+		t.Logf("Unexpected sheet:\n%s", out.Sheet(universe))
+		t.Fail()
+	}
+}
+
+func Test_Wizard_Complete_Points(t *testing.T) {
+	cases := []struct {
+		generation Generation
+		given      map[string]int
+		out        map[string]int
+		err        bool
+	}{
+		{
+			generation: Generation{Method: GenerationPoints, Base: 25, Points: 40},
+			given:      map[string]int{"ws": 45},
+			out:        map[string]int{"WS": 45, "BS": 25, "STR": 25},
+		},
+		{
+			generation: Generation{Method: GenerationPoints, Base: 25, Points: 40, Max: 20},
+			given:      map[string]int{"WS": 50},
+			err:        true,
+		},
+		{
+			generation: Generation{Method: GenerationPoints, Base: 25, Points: 40},
+			given:      map[string]int{"WS": 20},
+			err:        true,
+		},
+		{
+			generation: Generation{Method: GenerationPoints, Base: 25, Points: 15},
+			given:      map[string]int{"WS": 35, "BS": 35},
+			err:        true,
+		},
+	}
+
+	for i, c := range cases {
+		universe := newWizardUniverse()
+		universe.Generation = c.generation
+		universe.Characteristics = append(universe.Characteristics, Characteristic{Name: "STR"})
+		wizard := Wizard{Universe: universe, Output: ioutil.Discard, Rand: rand.New(rand.NewSource(1))}
+
+		out, err := wizard.Complete(Draft{
+			Name: "Test",
+			Metas: map[string]Meta{
+				"homeworld": {Label: "feral world"},
+				"role":      {Label: "Seeker"},
+			},
+			Characteristics: c.given,
+			XP:              IntP(0),
+		})
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(out.Characteristics, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out.Characteristics)
+			t.Fail()
+		}
+	}
+}
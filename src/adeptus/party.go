@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bradfitz/slice"
+)
+
+// Member is a character of a party, along with the sheet it was compiled from.
+type Member struct {
+	Sheet     Sheet
+	Character *Character
+}
+
+// Party is a group of characters compiled against the same universe.
+type Party []Member
+
+// Best holds the members having the best value for a characteristic or a skill.
+type Best struct {
+	Name    string
+	Value   int
+	Members []string
+}
+
+// Mismatch holds the rewards given by each member's sheet for a session
+// that didn't award the same experience to everyone, by member index.
+type Mismatch struct {
+	Date    time.Time
+	Rewards map[int]int
+}
+
+// NewParty compiles each sheet against the universe and returns the
+// corresponding party.
+func NewParty(universe Universe, sheets []Sheet, options Options) (Party, error) {
+	party := Party{}
+	for _, sheet := range sheets {
+		character, err := NewCharacter(universe, sheet, options)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", sheet.Header.Name, Render(err))
+		}
+
+		party = append(party, Member{
+			Sheet:     sheet,
+			Character: character,
+		})
+	}
+	return party, nil
+}
+
+// Aptitudes returns, for each aptitude of the universe, the name of the
+// members owning it.
+func (p Party) Aptitudes(universe Universe) map[string][]string {
+	coverage := make(map[string][]string)
+	for _, aptitude := range universe.Aptitudes {
+		coverage[string(aptitude)] = []string{}
+		for _, member := range p {
+			if _, found := member.Character.Aptitudes[string(aptitude)]; found {
+				coverage[string(aptitude)] = append(coverage[string(aptitude)], member.Character.Name)
+			}
+		}
+	}
+	return coverage
+}
+
+// BestCharacteristics returns the members having the highest value for each
+// characteristic of the universe.
+func (p Party) BestCharacteristics(universe Universe) []Best {
+	bests := []Best{}
+	for _, characteristic := range universe.Characteristics {
+		best := Best{Name: characteristic.Name}
+		for _, member := range p {
+			c, found := member.Character.Characteristics[characteristic.Name]
+			if !found {
+				continue
+			}
+			best = best.challenge(member.Character.Name, c.Value)
+		}
+		if len(best.Members) != 0 {
+			bests = append(bests, best)
+		}
+	}
+
+	slice.Sort(bests, func(i, j int) bool {
+		return bests[i].Name < bests[j].Name
+	})
+
+	return bests
+}
+
+// BestSkills returns the members having the highest tier for each skill known
+// by at least one member of the party.
+func (p Party) BestSkills() []Best {
+	skills := make(map[string]Best)
+	for _, member := range p {
+		for name, skill := range member.Character.Skills {
+			best, found := skills[name]
+			if !found {
+				best = Best{Name: skill.FullName()}
+			}
+			skills[name] = best.challenge(member.Character.Name, skill.Tier)
+		}
+	}
+
+	bests := []Best{}
+	for _, best := range skills {
+		bests = append(bests, best)
+	}
+
+	slice.Sort(bests, func(i, j int) bool {
+		return bests[i].Name < bests[j].Name
+	})
+
+	return bests
+}
+
+// challenge returns the best value between the current one and the given
+// member's.
+func (b Best) challenge(member string, value int) Best {
+	switch {
+	case len(b.Members) == 0 || value > b.Value:
+		b.Value = value
+		b.Members = []string{member}
+	case value == b.Value:
+		b.Members = append(b.Members, member)
+	}
+	return b
+}
+
+// MissingSkills returns the skills of the universe that no member of the
+// party knows, whatever the speciality.
+func (p Party) MissingSkills(universe Universe) []string {
+	missing := []string{}
+	for _, skill := range universe.Skills {
+		found := false
+		for _, member := range p {
+			for _, s := range member.Character.Skills {
+				if strings.EqualFold(s.Name, skill.Name) {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			missing = append(missing, skill.Name)
+		}
+	}
+
+	slice.Sort(missing, func(i, j int) bool {
+		return missing[i] < missing[j]
+	})
+
+	return missing
+}
+
+// DuplicateTalents returns, for each talent owned by more than one member, the
+// name of the members owning it.
+func (p Party) DuplicateTalents() map[string][]string {
+	owners := make(map[string][]string)
+	for _, member := range p {
+		for name := range member.Character.Talents {
+			owners[name] = append(owners[name], member.Character.Name)
+		}
+	}
+
+	for name, members := range owners {
+		if len(members) < 2 {
+			delete(owners, name)
+		}
+	}
+	return owners
+}
+
+// Mismatches returns the sessions, identified by their date, for which the
+// members' sheets do not agree on the reward. Members absent from a session
+// are ignored.
+func (p Party) Mismatches() []Mismatch {
+	rewards := make(map[time.Time]map[int]int)
+	for i, member := range p {
+		for _, session := range member.Sheet.Sessions {
+			if _, found := rewards[session.Date]; !found {
+				rewards[session.Date] = make(map[int]int)
+			}
+
+			reward := 0
+			if session.Reward != nil {
+				reward = *session.Reward
+			}
+			rewards[session.Date][i] += reward
+		}
+	}
+
+	mismatches := []Mismatch{}
+	for date, members := range rewards {
+		values := make(map[int]struct{})
+		for _, reward := range members {
+			values[reward] = struct{}{}
+		}
+		if len(values) > 1 {
+			mismatches = append(mismatches, Mismatch{
+				Date:    date,
+				Rewards: members,
+			})
+		}
+	}
+
+	slice.Sort(mismatches, func(i, j int) bool {
+		return mismatches[i].Date.Before(mismatches[j].Date)
+	})
+
+	return mismatches
+}
+
+// Print the party overview on the screen.
+func (p Party) Print(universe Universe) {
+
+	// Print the experience of each member.
+	fmt.Printf("%s\n", theme.Title("Experience"))
+
+	w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+	for _, member := range p {
+		c := member.Character
+		fmt.Fprintf(w, "%s\t%d/%d\t(%s)\n", c.Name, c.Spent, c.Experience, theme.Value(c.Experience-c.Spent))
+	}
+	w.Flush()
+
+	// Print the sessions rewards that differ between members.
+	mismatches := p.Mismatches()
+	if len(mismatches) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Rewards mismatches"))
+
+		w = tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+		for _, mismatch := range mismatches {
+			rewards := []string{}
+			for i, reward := range mismatch.Rewards {
+				rewards = append(rewards, fmt.Sprintf("%s [%d]", p[i].Character.Name, reward))
+			}
+			slice.Sort(rewards, func(i, j int) bool {
+				return rewards[i] < rewards[j]
+			})
+			fmt.Fprintf(w, "%s\t%s\n", mismatch.Date.Format("2006/01/02"), strings.Join(rewards, ", "))
+		}
+		w.Flush()
+	}
+
+	// Print the aptitudes coverage.
+	coverage := p.Aptitudes(universe)
+	if len(coverage) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Aptitudes"))
+
+		aptitudes := []string{}
+		for aptitude := range coverage {
+			aptitudes = append(aptitudes, aptitude)
+		}
+		slice.Sort(aptitudes, func(i, j int) bool {
+			return aptitudes[i] < aptitudes[j]
+		})
+
+		w = tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+		for _, aptitude := range aptitudes {
+			members := strings.Join(coverage[aptitude], ", ")
+			if len(members) == 0 {
+				members = theme.Error("none")
+			}
+			fmt.Fprintf(w, "%s\t%s\n", strings.Title(aptitude), members)
+		}
+		w.Flush()
+	}
+
+	// Print the best member for each characteristic.
+	characteristics := p.BestCharacteristics(universe)
+	if len(characteristics) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Characteristics"))
+
+		w = tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+		for _, best := range characteristics {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", best.Name, theme.Value(best.Value), strings.Join(best.Members, ", "))
+		}
+		w.Flush()
+	}
+
+	// Print the best member for each skill.
+	skills := p.BestSkills()
+	if len(skills) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Skills"))
+
+		w = tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+		for _, best := range skills {
+			fmt.Fprintf(w, "%s\t+%s\t%s\n", strings.Title(best.Name), theme.Value((best.Value-1)*10), strings.Join(best.Members, ", "))
+		}
+		w.Flush()
+	}
+
+	// Print the skills nobody knows.
+	missing := p.MissingSkills(universe)
+	if len(missing) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Missing skills"))
+		for _, skill := range missing {
+			fmt.Printf("%s\n", strings.Title(skill))
+		}
+	}
+
+	// Print the talents owned by several members.
+	duplicates := p.DuplicateTalents()
+	if len(duplicates) != 0 {
+		fmt.Printf("\n%s\n", theme.Title("Duplicate talents"))
+
+		talents := []string{}
+		for talent := range duplicates {
+			talents = append(talents, talent)
+		}
+		slice.Sort(talents, func(i, j int) bool {
+			return talents[i] < talents[j]
+		})
+
+		w = tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+		for _, talent := range talents {
+			fmt.Fprintf(w, "%s\t%s\n", strings.Title(talent), strings.Join(duplicates[talent], ", "))
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newPartyMember(name string, skills []Skill, talents []Talent, sessions []Session) Member {
+	c := &Character{
+		Name:    name,
+		Skills:  make(map[string]Skill),
+		Talents: make(map[string]Talent),
+	}
+	for _, skill := range skills {
+		c.Skills[skill.FullName()] = skill
+	}
+	for _, talent := range talents {
+		c.Talents[talent.FullName()] = talent
+	}
+	return Member{
+		Sheet:     Sheet{Sessions: sessions},
+		Character: c,
+	}
+}
+
+func Test_Party_BestSkills(t *testing.T) {
+	party := Party{
+		newPartyMember("alice", []Skill{{Name: "Awareness", Tier: 2}, {Name: "Dodge", Tier: 1}}, nil, nil),
+		newPartyMember("bob", []Skill{{Name: "Awareness", Tier: 1}, {Name: "Dodge", Tier: 1}}, nil, nil),
+	}
+
+	out := party.BestSkills()
+	expected := []Best{
+		{Name: "Awareness", Value: 2, Members: []string{"alice"}},
+		{Name: "Dodge", Value: 1, Members: []string{"alice", "bob"}},
+	}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Logf("Unexpected output:")
+		t.Logf("	Expected %v", expected)
+		t.Logf("	Having %v", out)
+		t.Fail()
+	}
+}
+
+func Test_Party_MissingSkills(t *testing.T) {
+	universe := Universe{
+		Skills: []Skill{
+			{Name: "Awareness"},
+			{Name: "Common Lore"},
+			{Name: "Dodge"},
+		},
+	}
+
+	party := Party{
+		newPartyMember("alice", []Skill{{Name: "Awareness", Tier: 1}}, nil, nil),
+		newPartyMember("bob", []Skill{{Name: "common lore", Speciality: "Imperium", Tier: 1}}, nil, nil),
+	}
+
+	out := party.MissingSkills(universe)
+	expected := []string{"Dodge"}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Logf("Unexpected output:")
+		t.Logf("	Expected %v", expected)
+		t.Logf("	Having %v", out)
+		t.Fail()
+	}
+}
+
+func Test_Party_DuplicateTalents(t *testing.T) {
+	party := Party{
+		newPartyMember("alice", nil, []Talent{{Name: "Iron Jaw"}, {Name: "Catfall"}}, nil),
+		newPartyMember("bob", nil, []Talent{{Name: "Iron Jaw"}}, nil),
+	}
+
+	out := party.DuplicateTalents()
+	expected := map[string][]string{
+		"Iron Jaw": []string{"alice", "bob"},
+	}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Logf("Unexpected output:")
+		t.Logf("	Expected %v", expected)
+		t.Logf("	Having %v", out)
+		t.Fail()
+	}
+}
+
+func Test_Party_Mismatches(t *testing.T) {
+	first := time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2015, time.July, 8, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		party Party
+		out   []Mismatch
+	}{
+		{
+			party: Party{
+				newPartyMember("alice", nil, nil, []Session{
+					{Date: first, Reward: IntP(750)},
+					{Date: second, Reward: IntP(500)},
+				}),
+				newPartyMember("bob", nil, nil, []Session{
+					{Date: first, Reward: IntP(750)},
+					{Date: second, Reward: IntP(400)},
+				}),
+				newPartyMember("carol", nil, nil, []Session{
+					{Date: first, Reward: IntP(750)},
+				}),
+			},
+			out: []Mismatch{
+				{
+					Date:    second,
+					Rewards: map[int]int{0: 500, 1: 400},
+				},
+			},
+		},
+		{
+			// Members sharing a name are told apart.
+			party: Party{
+				newPartyMember("alice", nil, nil, []Session{
+					{Date: first, Reward: IntP(750)},
+				}),
+				newPartyMember("alice", nil, nil, []Session{
+					{Date: first, Reward: IntP(500)},
+				}),
+			},
+			out: []Mismatch{
+				{
+					Date:    first,
+					Rewards: map[int]int{0: 750, 1: 500},
+				},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		out := c.party.Mismatches()
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// refundable returns true if reverting an upgrade of the coster refunds a
+// purchase. The gauges and conditions aren't purchased.
+func refundable(coster Coster) bool {
+	switch coster.(type) {
+	case Gauge, Condition:
+		return false
+	}
+	return true
+}
+
+// purchase returns the index in the history of the last purchase reverted by
+// the upgrade. The upgrades granted by a background, an elite advance or a
+// threshold aren't purchases.
+func (c *Character) purchase(upgrade Upgrade) (int, error) {
+	for i := len(c.History) - 1; i >= 0; i-- {
+		record := c.History[i]
+		if record.Mark != MarkApply || !strings.EqualFold(record.Name, upgrade.Name) {
+			continue
+		}
+		if len(record.Background) != 0 || len(record.Elite) != 0 || len(record.Threshold) != 0 {
+			continue
+		}
+		return i, nil
+	}
+	return 0, NewError(UnmatchedRefund, upgrade.Position(), upgrade.Name)
+}
+
+// Refunded returns the experience given back for a purchase of the given
+// cost.
+func (u Universe) Refunded(cost int) int {
+	if u.Refund == nil {
+		return cost
+	}
+	return int(float64(cost) * *u.Refund)
+}
+
+// checkRefund checks the refunded fraction of the cost of the purchases.
+func checkRefund(refund *float64) error {
+	if refund != nil && (*refund < 0 || *refund > 1) {
+		return fmt.Errorf("the refund %v isn't between 0 and 1", *refund)
+	}
+	return nil
+}
+
+// undo reverts the purchase refunded by the upgrade. A characteristic loses
+// the value of the purchase along with its tier, whatever the value of the
+// refunding upgrade.
+func (c *Character) undo(coster Coster, purchase Upgrade, upgrade Upgrade) error {
+	characteristic, ok := coster.(Characteristic)
+	if !ok {
+		return coster.Apply(c, upgrade)
+	}
+
+	value := c.Characteristics[characteristic.Name].Value
+	revert := upgrade
+	revert.Mark = MarkSpecial
+	revert.Name = negate(purchase.Name)
+	err := characteristic.Apply(c, revert)
+	if err != nil {
+		return err
+	}
+
+	undone := c.Characteristics[characteristic.Name]
+	undone.Tier--
+	undone.Advance += undone.Value - value
+	c.Characteristics[characteristic.Name] = undone
+	return nil
+}
+
+// unbalance takes the refunded purchase out of the balance of its session,
+// either the current one or an earlier one, and returns the experience it
+// spent.
+func (c *Character) unbalance(purchase Upgrade, current *Balance) int {
+	remove := func(balance *Balance) (int, bool) {
+		for i, record := range balance.Upgrades {
+			if record.Line != purchase.Line || record.File != purchase.File || record.Name != purchase.Name || record.Mark != purchase.Mark {
+				continue
+			}
+			cost := 0
+			if record.Cost != nil {
+				cost = *record.Cost
+			}
+			balance.Upgrades = append(balance.Upgrades[:i:i], balance.Upgrades[i+1:]...)
+			balance.Spent -= cost
+			return cost, true
+		}
+		return 0, false
+	}
+
+	if cost, found := remove(current); found {
+		return cost
+	}
+
+	// The experience remaining after the session of the purchase grows by its
+	// cost.
+	for i := len(c.Balances) - 1; i >= 0; i-- {
+		cost, found := remove(&c.Balances[i])
+		if !found {
+			continue
+		}
+		for j := i; j < len(c.Balances); j++ {
+			c.Balances[j].Remaining += cost
+		}
+		return cost
+	}
+	return 0
+}
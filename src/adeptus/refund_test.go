@@ -0,0 +1,165 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_NewCharacter_Refund(t *testing.T) {
+	half := 0.5
+
+	session := func(line int, upgrades ...Upgrade) Session {
+		return Session{Reward: IntP(1000), Upgrades: upgrades, Line: line}
+	}
+
+	cases := []struct {
+		refund   *float64
+		options  Options
+		sessions []Session
+		spent    int
+		history  int
+		tier     int
+		strength int
+		err      bool
+		code     ErrorCode
+	}{
+		// Without refund mode, the revert is free.
+		{
+			options: Options{},
+			sessions: []Session{
+				session(1, Upgrade{Mark: MarkApply, Name: "Awareness", Line: 2}),
+				session(3, Upgrade{Mark: MarkRevert, Name: "Awareness", Cost: IntP(0), Line: 4}),
+			},
+			spent:    200,
+			history:  2,
+			tier:     0,
+			strength: 30,
+		},
+		// The refund gives back the cost of the last purchase.
+		{
+			options: Options{Refund: true},
+			sessions: []Session{
+				session(1, Upgrade{Mark: MarkApply, Name: "Awareness", Line: 2}, Upgrade{Mark: MarkApply, Name: "Awareness", Line: 3}),
+				session(4, Upgrade{Mark: MarkRevert, Name: "Awareness", Cost: IntP(0), Line: 5}),
+			},
+			spent:    200,
+			history:  2,
+			tier:     1,
+			strength: 30,
+		},
+		// The universe may refund a fraction of the cost.
+		{
+			refund:  &half,
+			options: Options{Refund: true},
+			sessions: []Session{
+				session(1, Upgrade{Mark: MarkApply, Name: "Awareness", Line: 2}),
+				session(3, Upgrade{Mark: MarkRevert, Name: "Awareness", Cost: IntP(0), Line: 4}),
+			},
+			spent:    100,
+			history:  1,
+			tier:     0,
+			strength: 30,
+		},
+		// The refund of a characteristic takes back its value.
+		{
+			options: Options{Refund: true},
+			sessions: []Session{
+				session(1, Upgrade{Mark: MarkApply, Name: "STR +5", Line: 2}, Upgrade{Mark: MarkApply, Name: "Awareness", Line: 3}),
+				session(4, Upgrade{Mark: MarkRevert, Name: "STR +5", Cost: IntP(0), Line: 5}),
+			},
+			spent:    200,
+			history:  2,
+			tier:     1,
+			strength: 30,
+		},
+		// A refund needs an earlier purchase.
+		{
+			options: Options{Refund: true},
+			sessions: []Session{
+				session(1, Upgrade{Mark: MarkApply, Name: "Awareness", Line: 2}),
+				session(3, Upgrade{Mark: MarkRevert, Name: "Dodge", Cost: IntP(0), Line: 4}),
+			},
+			err:  true,
+			code: UnmatchedRefund,
+		},
+	}
+
+	for i, c := range cases {
+		universe := Universe{
+			Characteristics: []Characteristic{
+				{Name: "STR"},
+			},
+			Skills: []Skill{
+				{Name: "Awareness"},
+				{Name: "Dodge"},
+			},
+			Costs: CostMatrix{
+				"characteristic": {0: {1: 250}},
+				"skill":          {0: {1: 200, 2: 400}},
+			},
+			Refund: c.refund,
+		}
+
+		sheet := Sheet{
+			Characteristics: Characteristics{{Mark: MarkSpecial, Name: "STR 30", Cost: IntP(0)}},
+			Sessions:        c.sessions,
+		}
+		character, err := NewCharacter(universe, sheet, c.options)
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+			continue
+		}
+		if err != nil {
+			if err.(Error).Code != c.code {
+				t.Logf("Unexpected error on case %d:", i+1)
+				t.Logf("	Expected %s", NewError(c.code))
+				t.Logf("	Having %s", err)
+				t.Fail()
+			}
+			continue
+		}
+
+		if character.Spent != c.spent {
+			t.Logf("Unexpected spent experience on case %d: expected %d, having %d", i+1, c.spent, character.Spent)
+			t.Fail()
+		}
+		if len(character.History) != c.history {
+			t.Logf("Unexpected history on case %d: expected %d records, having %d", i+1, c.history, len(character.History))
+			t.Fail()
+		}
+		if character.Skills["Awareness"].Tier != c.tier {
+			t.Logf("Unexpected tier on case %d: expected %d, having %d", i+1, c.tier, character.Skills["Awareness"].Tier)
+			t.Fail()
+		}
+
+		strength := character.Characteristics["STR"]
+		if strength.Value != c.strength || strength.Tier != 0 || strength.Advance != 0 {
+			t.Logf("Unexpected STR on case %d: expected %d, having %d (tier %d, advance %d)", i+1, c.strength, strength.Value, strength.Tier, strength.Advance)
+			t.Fail()
+		}
+
+		// The refund is part of the balance of its session.
+		last := character.Balances[len(character.Balances)-1]
+		if len(last.Upgrades) != 1 || last.Spent != *last.Upgrades[0].Cost {
+			t.Logf("Unexpected balance on case %d: %+v", i+1, last)
+			t.Fail()
+		}
+
+		// The balances hold the history, and add up to the experience
+		// spent.
+		records, earned, spent := 0, 0, 0
+		for j, balance := range character.Balances {
+			records += len(balance.Upgrades)
+			earned += balance.Earned
+			spent += balance.Spent
+			if balance.Remaining != earned-spent {
+				t.Logf("Unexpected remaining experience on case %d, session %d: expected %d, having %d", i+1, j+1, earned-spent, balance.Remaining)
+				t.Fail()
+			}
+		}
+		if records != len(character.History) || spent != character.Spent {
+			t.Logf("Unexpected balances on case %d: %+v", i+1, character.Balances)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Repricing is the cost of an upgrade of the sheet's sessions under the
+// character's universe and under another universe.
+type Repricing struct {
+	Session Session
+	Upgrade Upgrade
+	Old     int
+	New     int
+
+	// Err is the reason the upgrade couldn't be applied under the other
+	// universe, if any.
+	Err error
+}
+
+// Delta returns the difference of cost of the upgrade between the two
+// universes.
+func (r Repricing) Delta() int {
+	return r.New - r.Old
+}
+
+// location identifies an upgrade of the sheet.
+type location struct {
+	File string
+	Line int
+}
+
+// Reprice replays the sheet of the character under another universe, and
+// returns the cost of each upgrade of the sessions under both universes,
+// along with the repriced character. The upgrades that can't be applied
+// under the other universe are dropped from the replay. Unless explicit is
+// true, the explicit costs of the applied upgrades are computed again from
+// the other universe, except for the special rules.
+func Reprice(character *Character, universe Universe, sheet Sheet, options Options, explicit bool) ([]Repricing, *Character, error) {
+
+	// The repriced character may very well spend more than it earns.
+	options.AllowDebt = true
+
+	// Copy the sessions, as the replay may alter them.
+	sessions := make([]Session, len(sheet.Sessions))
+	for i, session := range sheet.Sessions {
+		session.Upgrades = append([]Upgrade{}, session.Upgrades...)
+		for j, upgrade := range session.Upgrades {
+			if explicit || upgrade.Mark != MarkApply {
+				continue
+			}
+			coster, found := universe.FindCoster(upgrade)
+			if _, rule := coster.(Rule); found && !rule {
+				session.Upgrades[j].Cost = nil
+			}
+		}
+		sessions[i] = session
+	}
+	sheet.Sessions = sessions
+
+	// Replay the sheet, removing the upgrade responsible of each error until
+	// the character compiles.
+	failures := make(map[location]error)
+	var repriced *Character
+	for repriced == nil {
+		c, err := NewCharacter(universe, sheet, options)
+		if err == nil {
+			repriced = c
+			break
+		}
+
+		e, ok := err.(Error)
+		if !ok {
+			return nil, nil, err
+		}
+		position, ok := e.Position()
+		if !ok || !sheet.removeUpgrade(location{File: position.File, Line: position.Line}) {
+			return nil, nil, err
+		}
+		failures[location{File: position.File, Line: position.Line}] = err
+	}
+
+	// Index the new costs of the upgrades.
+	costs := make(map[location]int)
+	for _, balance := range repriced.Balances {
+		for _, record := range balance.Upgrades {
+			if len(record.Elite) != 0 {
+				continue
+			}
+			costs[location{File: record.File, Line: record.Line}] = *record.Cost
+		}
+	}
+
+	repricings := []Repricing{}
+	for _, balance := range character.Balances {
+		for _, record := range balance.Upgrades {
+			if len(record.Elite) != 0 {
+				continue
+			}
+			l := location{File: record.File, Line: record.Line}
+			repricings = append(repricings, Repricing{
+				Session: balance.Session,
+				Upgrade: record.Upgrade,
+				Old:     *record.Cost,
+				New:     costs[l],
+				Err:     failures[l],
+			})
+		}
+	}
+
+	return repricings, repriced, nil
+}
+
+// removeUpgrade removes the upgrade of the sessions at the given location,
+// and returns true if there was one.
+func (s *Sheet) removeUpgrade(l location) bool {
+	for i, session := range s.Sessions {
+		for j, upgrade := range session.Upgrades {
+			if upgrade.File != l.File || upgrade.Line != l.Line {
+				continue
+			}
+			s.Sessions[i].Upgrades = append(session.Upgrades[:j], session.Upgrades[j+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// PrintReprice displays the cost of each upgrade of the character under both
+// universes, followed by the total experience delta.
+func PrintReprice(character *Character, repriced *Character, repricings []Repricing) {
+	// Print the name.
+	fmt.Printf("%s\t%s\n", theme.Title("Name"), character.Name)
+
+	fmt.Printf("\n%s\n", theme.Title("Upgrades"))
+	w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+	for _, r := range repricings {
+		if r.Err != nil {
+			fmt.Fprintf(w, "%s\t%s %s\t%d\t%s\t\t%s\n",
+				r.Session.Date.Format("2006/01/02"),
+				r.Upgrade.Mark,
+				strings.Title(r.Upgrade.Name),
+				r.Old,
+				theme.Error("-"),
+				r.Err,
+			)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%s %s\t%d\t%d\t%+d\t\n",
+			r.Session.Date.Format("2006/01/02"),
+			r.Upgrade.Mark,
+			strings.Title(r.Upgrade.Name),
+			r.Old,
+			r.New,
+			r.Delta(),
+		)
+	}
+	w.Flush()
+
+	// The total includes the backgrounds, which may be priced differently too.
+	fmt.Printf("\n%s\t%d/%d -> %d/%d (%+d)\n",
+		theme.Title("Experience"),
+		character.Spent, character.Experience,
+		repriced.Spent, repriced.Experience,
+		repriced.Spent-character.Spent,
+	)
+}
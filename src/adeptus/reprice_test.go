@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_Reprice(t *testing.T) {
+	old := Universe{
+		Skills: []Skill{
+			{Name: "Awareness"},
+			{Name: "Dodge"},
+		},
+		Costs: CostMatrix{
+			"skill": {0: {1: 200, 2: 400}},
+		},
+	}
+
+	other := Universe{
+		Skills: []Skill{
+			{Name: "Awareness"},
+		},
+		Costs: CostMatrix{
+			"skill": {0: {1: 100, 2: 300}},
+		},
+	}
+
+	sheet := Sheet{
+		Sessions: []Session{
+			{
+				Reward: IntP(1000),
+				Upgrades: []Upgrade{
+					{Mark: MarkApply, Name: "Awareness", Line: 2},
+					{Mark: MarkApply, Name: "Dodge", Line: 3},
+					{Mark: MarkApply, Name: "Awareness", Cost: IntP(500), Line: 4},
+				},
+				Line: 1,
+			},
+		},
+	}
+
+	character, err := NewCharacter(old, sheet, Options{})
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	cases := []struct {
+		explicit bool
+		deltas   []int
+		failures []bool
+		spent    int
+	}{
+		{
+			explicit: true,
+			deltas:   []int{-100, -200, 0},
+			failures: []bool{false, true, false},
+			spent:    600,
+		},
+		{
+			explicit: false,
+			deltas:   []int{-100, -200, -200},
+			failures: []bool{false, true, false},
+			spent:    400,
+		},
+	}
+
+	for i, c := range cases {
+		repricings, repriced, err := Reprice(character, other, sheet, Options{}, c.explicit)
+		if err != nil {
+			t.Logf("Unexpected error on case %d: %s", i+1, err)
+			t.Fail()
+			continue
+		}
+
+		if len(repricings) != len(c.deltas) {
+			t.Logf("Unexpected repricings on case %d: %v", i+1, repricings)
+			t.Fail()
+			continue
+		}
+
+		for j, r := range repricings {
+			if r.Delta() != c.deltas[j] || (r.Err != nil) != c.failures[j] {
+				t.Logf("Unexpected repricing %d on case %d: %v", j+1, i+1, r)
+				t.Fail()
+			}
+		}
+
+		if repriced.Spent != c.spent {
+			t.Logf("Unexpected spent experience on case %d:", i+1)
+			t.Logf("	Expected %d", c.spent)
+			t.Logf("	Having %d", repriced.Spent)
+			t.Fail()
+		}
+	}
+
+	// The sheet must be left untouched.
+	if sheet.Sessions[0].Upgrades[2].Cost == nil || len(sheet.Sessions[0].Upgrades) != 3 {
+		t.Logf("Unexpected alteration of the sheet: %v", sheet.Sessions)
+		t.Fail()
+	}
+}
@@ -1,9 +1,13 @@
 package main
 
+// RulePrefix is the prefix of the explicit special rules upgrades.
+const RulePrefix = "rule"
+
 // Rule represent a special rule, which are generally home-made additions to the
 type Rule struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Modifiers   []Modifier `yaml:"modifiers"`
 }
 
 // Cost returns 0, a rule has no calculated cost.
@@ -19,11 +23,11 @@ func (r Rule) Apply(character *Character, upgrade Upgrade) error {
 
 	switch upgrade.Mark {
 	case MarkSpecial:
-		return NewError(ForbidenUpgradeMark, upgrade.Line)
+		return NewError(ForbidenUpgradeMark, upgrade.Position())
 	case MarkRevert:
 		_, found := character.Rules[r.Name]
 		if !found {
-			return NewError(ForbidenUpgradeLoss, upgrade.Line)
+			return NewError(ForbidenUpgradeLoss, upgrade.Position())
 		}
 		delete(character.Rules, r.Name)
 	case MarkApply:
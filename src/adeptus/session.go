@@ -7,14 +7,22 @@ import (
 )
 
 // Session blocks describe a game session, with its reward and upgrades to the
-// character.
+// character. A session can reference a session of the campaign log instead of
+// defining its own date and title.
 type Session struct {
-	Date     time.Time
-	Title    string
-	Reward   *int
-	Upgrades []Upgrade
+	Date      time.Time
+	Title     string
+	Reward    *int
+	Upgrades  []Upgrade
+	Reference string
+	Line      int
+	File      string
 }
 
+// ReferenceMark is the prefix of the headlines referencing a session of the
+// campaign log.
+const ReferenceMark = "@"
+
 // formats list the recognized date formats for session headlines
 var formats = []string{
 	"2006/01/02",
@@ -42,31 +50,26 @@ func parseSession(block []line) (Session, error) {
 		panic("empty line")
 	}
 
-	// Check if the first field is a recognized date
-	var err error
+	// Check if the headline is a reference to the campaign log, else the
+	// first field must be a recognized date
 	var date time.Time
-	for i, format := range formats {
-		// Try the format
-		date, err = time.Parse(format, fields[0])
+	var reference bool
+	if strings.HasPrefix(fields[0], ReferenceMark) {
+		reference = true
+		fields = strings.Fields(strings.TrimPrefix(strings.TrimSpace(headline.Text), ReferenceMark))
+	} else {
+		var err error
+		date, err = parseDate(fields[0])
+
+		// If we have an error, that's because no format matched
 		if err != nil {
-			continue
+			return Session{}, NewError(UndefinedSessionDate, headline.Locate(fields[0]))
 		}
 
-		// Put the format in the first
-		formats[0], formats[i] = formats[i], formats[0]
-
-		// The format is good, stop trying
-		break
-	}
-
-	// If we have an error, that's because no format matched
-	if err != nil {
-		return Session{}, NewError(UndefinedSessionDate, headline.Number)
+		// Remove the date from the fields
+		fields = fields[1:]
 	}
 
-	// Remove the date from the fields
-	fields = fields[1:]
-
 	// Check if a field seems to be a reward field
 	var reward *int
 	for i, field := range fields {
@@ -74,7 +77,7 @@ func parseSession(block []line) (Session, error) {
 		// If one end has the brackets but not the other, that's an error:
 		// brackets does by pairs, and are forbidden in the title
 		if strings.HasPrefix(field, "[") != strings.HasSuffix(field, "]") {
-			return Session{}, NewError(InvalidSessionReward, headline.Number)
+			return Session{}, NewError(InvalidSessionReward, headline.Locate(field))
 		}
 
 		// If the brackets are absents, that's not a reward, so skip the field.
@@ -86,12 +89,12 @@ func parseSession(block []line) (Session, error) {
 
 		// There can be only one reward on the line
 		if reward != nil {
-			return Session{}, NewError(DuplicateSessionReward, headline.Number)
+			return Session{}, NewError(DuplicateSessionReward, headline.Locate(field))
 		}
 
 		// Check position of the reward
 		if i != 0 && i != len(fields)-1 {
-			return Session{}, NewError(ForbidenRewardPosition, headline.Number)
+			return Session{}, NewError(ForbidenRewardPosition, headline.Locate(field))
 		}
 
 		// Trim the field to get the raw reward
@@ -100,7 +103,7 @@ func parseSession(block []line) (Session, error) {
 		// Parse the reward
 		r, err := strconv.Atoi(raw)
 		if err != nil {
-			return Session{}, NewError(InvalidSessionReward, headline.Number)
+			return Session{}, NewError(InvalidSessionReward, headline.Locate(field))
 		}
 		reward = &r
 
@@ -108,8 +111,16 @@ func parseSession(block []line) (Session, error) {
 		fields = append(fields[:i], fields[i+1:]...)
 	}
 
-	// The remaining fields are the title
+	// The remaining fields are the title, or the reference for the
+	// referencing headlines
 	title := strings.Join(fields, " ")
+	var ref string
+	if reference {
+		if len(title) == 0 {
+			return Session{}, NewError(EmptySessionReference, headline.Position())
+		}
+		ref, title = title, ""
+	}
 
 	// Parse the other lines as upgrades
 	upgrades := []Upgrade{}
@@ -124,9 +135,40 @@ func parseSession(block []line) (Session, error) {
 
 	// Return the session
 	return Session{
-		Date:     date,
-		Reward:   reward,
-		Title:    title,
-		Upgrades: upgrades,
+		Date:      date,
+		Reward:    reward,
+		Title:     title,
+		Upgrades:  upgrades,
+		Reference: ref,
+		Line:      headline.Number,
+		File:      headline.File,
 	}, nil
 }
+
+// Position returns the position of the session's headline in the sheet.
+func (s Session) Position() Position {
+	return Position{
+		File: s.File,
+		Line: s.Line,
+	}
+}
+
+// parseDate parse a session date in one of the recognized formats.
+func parseDate(raw string) (time.Time, error) {
+	var err error
+	var date time.Time
+	for i, format := range formats {
+		// Try the format
+		date, err = time.Parse(format, raw)
+		if err != nil {
+			continue
+		}
+
+		// Put the format in the first
+		formats[0], formats[i] = formats[i], formats[0]
+
+		// The format is good, stop trying
+		break
+	}
+	return date, err
+}
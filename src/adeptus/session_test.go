@@ -132,6 +132,7 @@ func Test_parseSession(t *testing.T) {
 				Title:    "success",
 				Reward:   nil,
 				Upgrades: []Upgrade{},
+				Line:     1,
 			},
 			err:   false,
 			panic: false,
@@ -145,6 +146,7 @@ func Test_parseSession(t *testing.T) {
 				Title:    "success",
 				Reward:   nil,
 				Upgrades: []Upgrade{},
+				Line:     1,
 			},
 			err:   false,
 			panic: false,
@@ -158,6 +160,7 @@ func Test_parseSession(t *testing.T) {
 				Title:    "success",
 				Reward:   nil,
 				Upgrades: []Upgrade{},
+				Line:     1,
 			},
 			err:   false,
 			panic: false,
@@ -171,6 +174,7 @@ func Test_parseSession(t *testing.T) {
 				Title:    "",
 				Reward:   IntP(250),
 				Upgrades: []Upgrade{},
+				Line:     1,
 			},
 			err:   false,
 			panic: false,
@@ -184,6 +188,7 @@ func Test_parseSession(t *testing.T) {
 				Title:    "success",
 				Reward:   IntP(250),
 				Upgrades: []Upgrade{},
+				Line:     1,
 			},
 			err:   false,
 			panic: false,
@@ -197,6 +202,7 @@ func Test_parseSession(t *testing.T) {
 				Title:    "success",
 				Reward:   IntP(250),
 				Upgrades: []Upgrade{},
+				Line:     1,
 			},
 			err:   false,
 			panic: false,
@@ -210,6 +216,7 @@ func Test_parseSession(t *testing.T) {
 				Title:    "success",
 				Reward:   IntP(250),
 				Upgrades: []Upgrade{},
+				Line:     1,
 			},
 			err:   false,
 			panic: false,
@@ -263,6 +270,56 @@ func Test_parseSession(t *testing.T) {
 						Line: 4,
 					},
 				},
+				Line: 1,
+			},
+			err:   false,
+			panic: false,
+		},
+		{
+			in: []string{
+				"@",
+			},
+			out:   Session{},
+			err:   true,
+			panic: false,
+		},
+		{
+			in: []string{
+				"@ [250]",
+			},
+			out:   Session{},
+			err:   true,
+			panic: false,
+		},
+		{
+			in: []string{
+				"@ First scenario",
+				"	+ WP +5",
+			},
+			out: Session{
+				Upgrades: []Upgrade{
+					{
+						Mark: "+",
+						Name: "WP +5",
+						Cost: nil,
+						Line: 2,
+					},
+				},
+				Reference: "First scenario",
+				Line:      1,
+			},
+			err:   false,
+			panic: false,
+		},
+		{
+			in: []string{
+				"@2001/04/28 [250]",
+			},
+			out: Session{
+				Reward:    IntP(250),
+				Upgrades:  []Upgrade{},
+				Reference: "2001/04/28",
+				Line:      1,
 			},
 			err:   false,
 			panic: false,
@@ -4,6 +4,9 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 // Sheet holds the informations of the character sheet: the character definition
@@ -14,20 +17,29 @@ type Sheet struct {
 	Characteristics Characteristics
 }
 
-// ParseSheet parse a Sheet from a io.Reader.
+// IncludeDirective is the directive replaced by the lines of another file.
+// The path of the included file is relative to the including file.
+const IncludeDirective = "!include"
+
+// ParseSheet parse a Sheet from a io.Reader. The included files are resolved
+// relative to the working directory.
 func ParseSheet(file io.Reader) (Sheet, error) {
-	scanner := bufio.NewScanner(file)
+	return parseSheet(file, "")
+}
+
+// parseSheet parse a Sheet from a io.Reader read from the given file name.
+// The included files are resolved relative to this file.
+func parseSheet(file io.Reader, name string) (Sheet, error) {
+	lines, err := readLines(file, name, []string{})
+	if err != nil {
+		return Sheet{}, err
+	}
+
 	buffer := [][]line{}
 	block := []line{}
 
 	// Scan each line.
-	i := 0
-	for scanner.Scan() {
-		i++
-		l := line{
-			Number: i,
-			Text:   scanner.Text(),
-		}
+	for _, l := range lines {
 
 		// Discard commented elements from line.
 		old := l.Text
@@ -51,11 +63,6 @@ func ParseSheet(file io.Reader) (Sheet, error) {
 		}
 	}
 
-	// In case of error, return now
-	if scanner.Err() != nil {
-		panic(fmt.Sprintf("unable to read sheet: %s", scanner.Err()))
-	}
-
 	// Append the last block to the buffer
 	if len(block) != 0 {
 		buffer = append(buffer, block)
@@ -95,3 +102,64 @@ func ParseSheet(file io.Reader) (Sheet, error) {
 		Characteristics: characteristics,
 	}, nil
 }
+
+// readLines returns the lines of the file, the include directives being
+// replaced by the lines of the included file. The stack holds the files
+// being read, to detect the inclusion cycles.
+func readLines(file io.Reader, name string, stack []string) ([]line, error) {
+	scanner := bufio.NewScanner(file)
+	lines := []line{}
+	stack = append(stack, filepath.Clean(name))
+
+	// Scan each line.
+	i := 0
+	for scanner.Scan() {
+		i++
+		l := line{
+			File:   name,
+			Number: i,
+			Text:   scanner.Text(),
+		}
+
+		// Keep the line as is if it isn't an include directive.
+		instruction := strings.TrimSpace(l.Instruction())
+		fields := strings.Fields(instruction)
+		if len(fields) == 0 || fields[0] != IncludeDirective {
+			lines = append(lines, l)
+			continue
+		}
+
+		// Resolve the path of the included file.
+		path := strings.TrimSpace(strings.TrimPrefix(instruction, IncludeDirective))
+		if len(path) == 0 {
+			return nil, NewError(InvalidInclude, l.Position())
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(name), path)
+		}
+
+		// Check the file isn't already being read.
+		if in(filepath.Clean(path), stack) {
+			return nil, NewError(IncludeCycle, l.Position(), path)
+		}
+
+		// Read the included file.
+		included, err := os.Open(path)
+		if err != nil {
+			return nil, NewError(UnreadableInclude, l.Position(), err)
+		}
+		sub, err := readLines(included, path, stack)
+		_ = included.Close()
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, sub...)
+	}
+
+	// In case of error, return now
+	if scanner.Err() != nil {
+		panic(fmt.Sprintf("unable to read sheet: %s", scanner.Err()))
+	}
+
+	return lines, nil
+}
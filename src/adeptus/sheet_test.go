@@ -3,6 +3,9 @@ package main
 import (
 	"errors"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -225,6 +228,7 @@ WP 25
 								Line: 12,
 							},
 						},
+						Line: 9,
 					},
 				},
 			},
@@ -272,3 +276,151 @@ WP 25
 	}
 
 }
+
+func Test_parseSheet_Include(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adeptus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	files := map[string]string{
+		"header.sheet": `Name: Celeste
+Origin: Somewhere
+`,
+		"sessions/first.sheet": `2015/06/01 Creation [500]
+	+ Awesomeskill
+`,
+		"sessions/broken.sheet": `2015/06/01 Creation [500]
+	x Awesomeskill
+`,
+		"sessions/cycle.sheet": `!include ../cycle.sheet
+`,
+		"cycle.sheet": `!include sessions/cycle.sheet
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	name := filepath.Join(dir, "celeste.sheet")
+	cases := []struct {
+		in  string
+		out Sheet
+		err string
+	}{
+		{
+			in: `!include header.sheet
+
+WP 25
+
+!include sessions/first.sheet // The first arc
+`,
+			out: Sheet{
+				Header: Header{
+					Name: "Celeste",
+					Metas: map[string][]Meta{
+						"origin": {
+							Meta{
+								Label: "Somewhere",
+								Line:  2,
+								File:  filepath.Join(dir, "header.sheet"),
+							},
+						},
+					},
+				},
+				Characteristics: Characteristics{
+					Upgrade{
+						Mark: MarkSpecial,
+						Name: "WP 25",
+						Cost: IntP(0),
+						Line: 3,
+						File: name,
+					},
+				},
+				Sessions: []Session{
+					{
+						Date:   time.Date(2015, time.June, 01, 0, 0, 0, 0, time.UTC),
+						Title:  "Creation",
+						Reward: IntP(500),
+						Upgrades: []Upgrade{
+							{
+								Mark: MarkApply,
+								Name: "Awesomeskill",
+								Line: 2,
+								File: filepath.Join(dir, "sessions/first.sheet"),
+							},
+						},
+						Line: 1,
+						File: filepath.Join(dir, "sessions/first.sheet"),
+					},
+				},
+			},
+		},
+		{
+			in: `!include header.sheet
+
+WP 25
+
+!include sessions/broken.sheet
+`,
+			err: filepath.Join(dir, "sessions/broken.sheet") + ":2:2: the upgrade mark is invalid",
+		},
+		{
+			in: `!include missing.sheet
+`,
+			err: name + ":1: unable to read the included file",
+		},
+		{
+			in: `!include
+`,
+			err: name + ":1: the included file is not defined",
+		},
+		{
+			in: `!include cycle.sheet
+`,
+			err: filepath.Join(dir, "sessions/cycle.sheet") + ":1: the file " + filepath.Join(dir, "cycle.sheet") + " is already included",
+		},
+	}
+
+	for i, c := range cases {
+		out, err := parseSheet(strings.NewReader(c.in), name)
+
+		if (err != nil) != (len(c.err) != 0) {
+			if err == nil {
+				t.Logf("Expected error on case %d", i+1)
+			} else {
+				t.Logf("Unexpected error on case %d: %s", i+1, err)
+			}
+			t.Fail()
+			continue
+		}
+
+		if err != nil {
+			if !strings.HasPrefix(err.Error(), c.err) {
+				t.Logf("Unexpected error on case %d:", i+1)
+				t.Logf("	Expected %s", c.err)
+				t.Logf("	Having %s", err)
+				t.Fail()
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
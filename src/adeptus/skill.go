@@ -9,6 +9,7 @@ type Skill struct {
 	Name       string     `yaml:"name"`
 	Aptitudes  []Aptitude `yaml:"aptitudes"`
 	Tier       int        `yaml:"tier"`
+	Alignments []string   `yaml:"alignments"`
 	Speciality string     `yaml:"-"`
 }
 
@@ -21,8 +22,13 @@ func (s Skill) Cost(universe Universe, character Character) (int, error) {
 		tier = character.Skills[s.FullName()].Tier
 	}
 
-	// Return the price as determined by the cost matrix.
-	return universe.Costs.Price("skill", character.Intersect(s.Aptitudes), tier+1)
+	// The skills are advances of the character's career, if any.
+	if career, found := universe.FindCareer(character.Career); found {
+		return career.Cost(universe, character, "skill", s.FullName(), tier)
+	}
+
+	// Return the price as determined by the cost model of the universe.
+	return universe.Price("skill", universe.Matches(character, s.Aptitudes, s.Alignments), tier+1, character)
 }
 
 // FullName return the name of the skill and it's speciality if defined.
@@ -46,7 +52,7 @@ func (s Skill) Apply(character *Character, upgrade Upgrade) error {
 
 	switch upgrade.Mark {
 	case MarkSpecial:
-		return NewError(ForbidenUpgradeMark, upgrade.Line)
+		return NewError(ForbidenUpgradeMark, upgrade.Position())
 	case MarkRevert:
 		s.Tier--
 	case MarkApply:
@@ -56,7 +62,7 @@ func (s Skill) Apply(character *Character, upgrade Upgrade) error {
 	// Remove the skill if it is negative.
 	if s.Tier <= 0 {
 		if !found {
-			return NewError(ForbidenUpgradeLoss, upgrade.Line)
+			return NewError(ForbidenUpgradeLoss, upgrade.Position())
 		}
 		delete(character.Skills, s.FullName())
 		return nil
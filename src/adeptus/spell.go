@@ -25,7 +25,7 @@ func (s Spell) Apply(character *Character, upgrade Upgrade) error {
 	// Get the gauge from the character.
 	_, found := character.Spells[s.Name]
 	if found {
-		return NewError(DuplicateUpgrade, upgrade.Line)
+		return NewError(DuplicateUpgrade, upgrade.Position())
 	}
 
 	// Set the spell to the map.
@@ -8,17 +8,24 @@ type Talent struct {
 	Description  string        `yaml:"description"`
 	Aptitudes    []Aptitude    `yaml:"aptitudes"`
 	Tier         int           `yaml:"tier"`
+	Alignments   []string      `yaml:"alignments"`
 	Requirements []Requirement `yaml:"requirements"`
 	Speciality   string        `yaml:"-"`
 	Value        int           `yaml:"-"`
 	Stackable    bool          `yaml:"stackable"`
+	Modifiers    []Modifier    `yaml:"modifiers"`
 }
 
 // Cost returns the cost of the talent given the character's aptitudes and the current tier.
 func (t Talent) Cost(universe Universe, character Character) (int, error) {
 
-	// Return the price as determined by the cost matrix.
-	return universe.Costs.Price("talent", character.Intersect(t.Aptitudes), t.Tier)
+	// The talents are advances of the character's career, if any.
+	if career, found := universe.FindCareer(character.Career); found {
+		return career.Cost(universe, character, "talent", t.FullName(), character.Talents[t.FullName()].Value)
+	}
+
+	// Return the price as determined by the cost model of the universe.
+	return universe.Price("talent", universe.Matches(character, t.Aptitudes, t.Alignments), t.Tier, character)
 }
 
 // FullName return the name of the talent and it's speciality if defined.
@@ -43,7 +50,7 @@ func (t Talent) Apply(character *Character, upgrade Upgrade) error {
 
 	switch upgrade.Mark {
 	case MarkSpecial:
-		return NewError(ForbidenUpgradeMark, upgrade.Line)
+		return NewError(ForbidenUpgradeMark, upgrade.Position())
 	case MarkRevert:
 		t.Value--
 	case MarkApply:
@@ -53,7 +60,7 @@ func (t Talent) Apply(character *Character, upgrade Upgrade) error {
 	// Remove the talent if it is negative.
 	if t.Value <= 0 {
 		if !found {
-			return NewError(ForbidenUpgradeLoss, upgrade.Line)
+			return NewError(ForbidenUpgradeLoss, upgrade.Position())
 		}
 		delete(character.Talents, t.FullName())
 		return nil
@@ -61,7 +68,7 @@ func (t Talent) Apply(character *Character, upgrade Upgrade) error {
 
 	// Check the talent is stackable.
 	if !t.Stackable && t.Value > 1 {
-		return NewError(DuplicateUpgrade, upgrade.Line)
+		return NewError(DuplicateUpgrade, upgrade.Position())
 	}
 
 	// Put it back on the map.
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/bradfitz/slice"
+)
+
+// templateFuncs returns the functions available to every template:
+// - title capitalizes the words of a name
+// - advances returns, for each tier of the type priced by the cost model of
+// the universe, true if it is reached by the given tier
+// - sort returns a sorted copy of a list, by the given field or method of its
+// elements if any
+// - pad pads a value with spaces up to the given width, on the right, or on
+// the left for a negative width
+func templateFuncs(universe Universe) map[string]interface{} {
+	return map[string]interface{}{
+		"title": title,
+		"advances": func(typ string, tier int) []bool {
+			tiers := universe.CostModel(typ).Tiers(typ)
+			if tier > tiers {
+				tiers = tier
+			}
+			advances := make([]bool, tiers)
+			for i := 0; i < tier; i++ {
+				advances[i] = true
+			}
+			return advances
+		},
+		"sort": sortList,
+		"pad":  pad,
+	}
+}
+
+// title capitalizes the words of the name, as the traits are displayed.
+func title(name string) string {
+	return strings.Title(name)
+}
+
+// textFuncs returns the functions available to the text templates, which
+// are the functions of every template along with the colours of the theme:
+// heading, value, warning and error.
+func textFuncs(universe Universe) map[string]interface{} {
+	funcs := templateFuncs(universe)
+	funcs["heading"] = theme.Title
+	funcs["value"] = theme.Value
+	funcs["warning"] = theme.Warning
+	funcs["error"] = theme.Error
+	return funcs
+}
+
+// sortList returns a copy of the list sorted by the value of its elements, or
+// by the value of the given field or method of its elements.
+func sortList(list interface{}, by ...string) (interface{}, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't sort %s", v.Kind())
+	}
+	if len(by) > 1 {
+		return nil, fmt.Errorf("can't sort by several keys")
+	}
+
+	keys := make([]reflect.Value, v.Len())
+	for i := range keys {
+		key := v.Index(i)
+		if len(by) != 0 {
+			var err error
+			key, err = field(key, by[0])
+			if err != nil {
+				return nil, err
+			}
+		}
+		keys[i] = key
+	}
+
+	// The keys are sorted along with the elements.
+	index := make([]int, len(keys))
+	for i := range index {
+		index[i] = i
+	}
+	slice.Sort(index, func(i, j int) bool {
+		return less(keys[index[i]], keys[index[j]])
+	})
+
+	out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	for i, j := range index {
+		out.Index(i).Set(v.Index(j))
+	}
+	return out.Interface(), nil
+}
+
+// field returns the value of the field or method of the given name.
+func field(v reflect.Value, name string) (reflect.Value, error) {
+	if method := v.MethodByName(name); method.IsValid() && method.Type().NumIn() == 0 && method.Type().NumOut() == 1 {
+		return method.Call(nil)[0], nil
+	}
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if f := v.FieldByName(name); f.IsValid() {
+			return f, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("can't sort by %s", name)
+}
+
+// less compares the numbers as numbers, and any other value as text.
+func less(a reflect.Value, b reflect.Value) bool {
+	for a.Kind() == reflect.Ptr && !a.IsNil() {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Ptr && !b.IsNil() {
+		b = b.Elem()
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if b.Kind() == a.Kind() {
+			return a.Int() < b.Int()
+		}
+	case reflect.Float32, reflect.Float64:
+		if b.Kind() == a.Kind() {
+			return a.Float() < b.Float()
+		}
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// pad pads the value with spaces up to the given width, on the right, or on
+// the left for a negative width.
+func pad(width int, value interface{}) string {
+	// The pointers, like the costs, are padded as their value.
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	s := fmt.Sprint(value)
+	if v.IsValid() {
+		s = fmt.Sprint(v.Interface())
+	}
+	n := width
+	if n < 0 {
+		n = -n
+	}
+	missing := n - utf8.RuneCountInString(s)
+	if missing <= 0 {
+		return s
+	}
+	if width < 0 {
+		return strings.Repeat(" ", missing) + s
+	}
+	return s + strings.Repeat(" ", missing)
+}
+
+// ExecuteTemplate executes the text template of the given file on the data,
+// writing on the standard output.
+func ExecuteTemplate(path string, universe Universe, data interface{}) error {
+	t, err := template.New(filepath.Base(path)).Funcs(textFuncs(universe)).ParseFiles(path)
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("invalid template:"), err)
+	}
+	err = t.Execute(os.Stdout, data)
+	if err != nil {
+		return fmt.Errorf("%s %s", theme.Error("invalid template:"), err)
+	}
+	return nil
+}
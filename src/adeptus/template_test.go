@@ -0,0 +1,123 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_sortList(t *testing.T) {
+	skills := []SkillView{
+		{Skill: Skill{Name: "Dodge", Tier: 2}, Bonus: 10},
+		{Skill: Skill{Name: "Awareness", Tier: 3}, Bonus: 20},
+		{Skill: Skill{Name: "Athletics", Tier: 1}},
+	}
+
+	cases := []struct {
+		list interface{}
+		by   []string
+		out  interface{}
+		err  bool
+	}{
+		{
+			list: []string{"b", "c", "a"},
+			out:  []string{"a", "b", "c"},
+		},
+		{
+			list: []int{10, 9, 100},
+			out:  []int{9, 10, 100},
+		},
+		{
+			list: skills,
+			by:   []string{"Bonus"},
+			out:  []SkillView{skills[2], skills[0], skills[1]},
+		},
+		{
+			list: skills,
+			by:   []string{"FullName"},
+			out:  []SkillView{skills[2], skills[1], skills[0]},
+		},
+		{
+			list: skills,
+			by:   []string{"Nope"},
+			err:  true,
+		},
+		{
+			list: "abc",
+			err:  true,
+		},
+	}
+
+	for i, c := range cases {
+		out, err := sortList(c.list, c.by...)
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_pad(t *testing.T) {
+	cases := []struct {
+		width int
+		value interface{}
+		out   string
+	}{
+		{width: 5, value: "WS", out: "WS   "},
+		{width: -5, value: 35, out: "   35"},
+		{width: 2, value: "Dodge", out: "Dodge"},
+		{width: -4, value: IntP(300), out: " 300"},
+	}
+
+	for i, c := range cases {
+		out := pad(c.width, c.value)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d: expected %q, having %q", i+1, c.out, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_templateFuncs_advances(t *testing.T) {
+	universe := Universe{
+		Costs: CostMatrix{
+			"skill": {0: {1: 200, 2: 400, 3: 600}},
+		},
+		CostRules: map[string]CostRule{
+			"talent":         {Table: map[int]map[int]int{0: {1: 300, 2: 600}}},
+			"characteristic": {Formula: "tier * 100", MaxTier: 4},
+			"psychic":        {Linear: &LinearCost{Base: 100}},
+		},
+	}
+	advances := templateFuncs(universe)["advances"].(func(string, int) []bool)
+
+	cases := []struct {
+		typ  string
+		tier int
+		out  []bool
+	}{
+		{typ: "skill", tier: 1, out: []bool{true, false, false}},
+		{typ: "talent", tier: 1, out: []bool{true, false}},
+		{typ: "characteristic", tier: 2, out: []bool{true, true, false, false}},
+		{typ: "psychic", tier: 1, out: []bool{true}},
+	}
+
+	for i, c := range cases {
+		out := advances(c.typ, c.tier)
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
@@ -4,9 +4,10 @@ import "github.com/fatih/color"
 
 // Theme is the struct responsible for output theming/colors.
 type Theme struct {
-	Title func(...interface{}) string
-	Error func(...interface{}) string
-	Value func(...interface{}) string
+	Title   func(...interface{}) string
+	Error   func(...interface{}) string
+	Warning func(...interface{}) string
+	Value   func(...interface{}) string
 }
 
 var theme Theme
@@ -14,5 +15,6 @@ var theme Theme
 func init() {
 	theme.Title = color.New(color.FgGreen, color.Bold).SprintFunc()
 	theme.Error = color.New(color.FgRed, color.Bold).SprintFunc()
+	theme.Warning = color.New(color.FgMagenta, color.Bold).SprintFunc()
 	theme.Value = color.New(color.FgYellow, color.Bold).SprintFunc()
 }
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
+	"regexp"
 	"strconv"
 	"strings"
-	
+
+	"github.com/bradfitz/slice"
 	"gopkg.in/yaml.v2"
 )
 
@@ -19,11 +22,127 @@ type Universe struct {
 	Talents         []Talent                `yaml:"talents"`
 	Spells          []Spell                 `yaml:"spells"`
 	Costs           CostMatrix              `yaml:"costs"`
+	CostRules       map[string]CostRule     `yaml:"cost_rules"`
+	Alignment       *Alignment              `yaml:"alignment"`
+	Careers         []Career                `yaml:"careers"`
+	Elites          []Elite                 `yaml:"elites"`
+	Conditions      []Condition             `yaml:"conditions"`
+	Rules           []Rule                  `yaml:"rules"`
+	Generation      Generation              `yaml:"generation"`
+	Refund          *float64                `yaml:"refund"`
+
+	DuplicateAptitudes AptitudePolicy `yaml:"duplicate_aptitudes"`
+}
+
+// yamlLine matches the line number and message of the YAML parser errors.
+var yamlLine = regexp.MustCompile(`line (\d+): (.*)`)
+
+// yamlName matches the name of an entry of the universe, the first group
+// being the margin before the name key.
+var yamlName = regexp.MustCompile(`^(\s*(?:-\s+)?)name:\s*(.*?)\s*$`)
+
+// yamlError returns the error of the YAML parser located on its first line,
+// each of its lines naming the entry holding the faulty line.
+func yamlError(raw []byte, name string, err error) error {
+	lines := strings.Split(string(raw), "\n")
+
+	var position *Position
+	messages := []string{}
+	for _, matches := range yamlLine.FindAllStringSubmatch(err.Error(), -1) {
+		number, _ := strconv.Atoi(matches[1])
+		message := matches[2]
+		if entry := yamlEntry(lines, number); len(entry) != 0 {
+			message = fmt.Sprintf("%s: %s", entry, message)
+		}
+
+		// The following lines are located in their message.
+		if position != nil {
+			message = fmt.Sprintf("%s: %s", Position{File: name, Line: number}, message)
+		} else {
+			position = &Position{File: name, Line: number}
+		}
+		messages = append(messages, message)
+	}
+
+	if position == nil {
+		return NewError(InvalidUniverse, Position{File: name}, err)
+	}
+	return NewError(InvalidUniverse, *position, strings.Join(messages, "\n"))
+}
+
+// yamlEntry returns the entry of the universe holding the given line, like
+// "skills entry Awareness", the section alone if the entry has no name, or an
+// empty string outside of any section.
+func yamlEntry(lines []string, number int) string {
+	if number < 1 || number > len(lines) {
+		return ""
+	}
+	indentation := func(text string) int {
+		return len(text) - len(strings.TrimLeft(text, " "))
+	}
+
+	// Go up to the section, looking for the name of the innermost entry
+	// holding the line.
+	section, entry, item := "", "", -1
+	depth := indentation(lines[number-1])
+	for i := number - 1; i >= 0; i-- {
+		text := lines[i]
+		trimmed := strings.TrimSpace(text)
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		column := indentation(text)
+		if column == 0 {
+			if i != number-1 {
+				section = strings.TrimSuffix(trimmed, ":")
+			}
+			break
+		}
+		if len(entry) == 0 && item < 0 {
+			if matches := yamlName.FindStringSubmatch(text); matches != nil && len(matches[1]) <= depth {
+				entry = matches[2]
+			} else if strings.HasPrefix(trimmed, "-") && column <= depth {
+				item = i
+			}
+		}
+		if column < depth {
+			depth = column
+		}
+	}
+
+	// The name of the entry may follow the line.
+	if item >= 0 && len(section) != 0 {
+		column := indentation(lines[item])
+		for _, text := range lines[item+1:] {
+			if len(strings.TrimSpace(text)) != 0 && indentation(text) <= column {
+				break
+			}
+			if matches := yamlName.FindStringSubmatch(text); matches != nil && len(matches[1]) == column+2 {
+				entry = matches[2]
+				break
+			}
+		}
+	}
+
+	switch {
+	case len(section) == 0:
+		return ""
+	case len(entry) == 0:
+		return section
+	default:
+		return fmt.Sprintf("%s entry %s", section, strings.Trim(entry, `"'`))
+	}
 }
 
 // ParseUniverse load an from a plain YAML file.
 // It returns a well-formed universe that describe all the components of a game setting.
 func ParseUniverse(file io.Reader) (Universe, error) {
+	return parseUniverse(file, "")
+}
+
+// parseUniverse load an universe from a plain YAML file read from the given
+// file name, which is used to locate the errors.
+func parseUniverse(file io.Reader, name string) (Universe, error) {
 
 	// Open and parse universe.
 	raw, err := ioutil.ReadAll(file)
@@ -33,7 +152,116 @@ func ParseUniverse(file io.Reader) (Universe, error) {
 	universe := Universe{}
 	err = yaml.Unmarshal(raw, &universe)
 	if err != nil {
-		return Universe{}, err
+		return Universe{}, yamlError(raw, name, err)
+	}
+
+	// Check each entry is named, in a fixed order.
+	type entries struct {
+		typ   string
+		names []string
+	}
+	named := []entries{
+		{typ: "characteristic"},
+		{typ: "gauge"},
+		{typ: "skill"},
+		{typ: "talent"},
+		{typ: "spell"},
+		{typ: "career"},
+		{typ: "elite"},
+		{typ: "condition"},
+		{typ: "rule"},
+	}
+	for _, c := range universe.Characteristics {
+		named[0].names = append(named[0].names, c.Name)
+	}
+	for _, g := range universe.Gauges {
+		named[1].names = append(named[1].names, g.Name)
+	}
+	for _, s := range universe.Skills {
+		named[2].names = append(named[2].names, s.Name)
+	}
+	for _, t := range universe.Talents {
+		named[3].names = append(named[3].names, t.Name)
+	}
+	for _, s := range universe.Spells {
+		named[4].names = append(named[4].names, s.Name)
+	}
+	for _, c := range universe.Careers {
+		named[5].names = append(named[5].names, c.Name)
+	}
+	for _, e := range universe.Elites {
+		named[6].names = append(named[6].names, e.Name)
+	}
+	for _, c := range universe.Conditions {
+		named[7].names = append(named[7].names, c.Name)
+	}
+	for _, r := range universe.Rules {
+		named[8].names = append(named[8].names, r.Name)
+	}
+	types := []string{}
+	for typ := range universe.Backgrounds {
+		types = append(types, typ)
+	}
+	slice.Sort(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	for _, typ := range types {
+		e := entries{typ: typ}
+		for _, b := range universe.Backgrounds[typ] {
+			e.names = append(e.names, b.Name)
+		}
+		named = append(named, e)
+	}
+	for _, e := range named {
+		for i, n := range e.names {
+			if len(strings.TrimSpace(n)) == 0 {
+				return Universe{}, NewError(UnnamedUniverseEntry, Position{File: name}, e.typ, i+1)
+			}
+		}
+	}
+
+	// Compile the cost rules.
+	for typ, rule := range universe.CostRules {
+		err := rule.Compile()
+		if err != nil {
+			return Universe{}, NewError(InvalidCostRule, Position{File: name}, typ, err)
+		}
+		universe.CostRules[typ] = rule
+	}
+
+	// Check the gauges.
+	for _, g := range universe.Gauges {
+		err := g.Check()
+		if err != nil {
+			return Universe{}, NewError(InvalidGauge, Position{File: name}, g.Name, err)
+		}
+	}
+
+	// Check the generation method. Those of the backgrounds are checked
+	// once the files are merged, as they may rely on another file's.
+	err = universe.Generation.Check()
+	if err != nil {
+		return Universe{}, NewError(InvalidUniverse, Position{File: name}, err)
+	}
+
+	// Check the refunded fraction.
+	err = checkRefund(universe.Refund)
+	if err != nil {
+		return Universe{}, NewError(InvalidUniverse, Position{File: name}, err)
+	}
+
+	// Check the duplicate aptitudes policy.
+	err = universe.DuplicateAptitudes.Check()
+	if err != nil {
+		return Universe{}, NewError(InvalidUniverse, Position{File: name}, err)
+	}
+
+	// Check the alignment rules.
+	if universe.Alignment != nil {
+		err := universe.Alignment.Check()
+		if err != nil {
+			return Universe{}, NewError(InvalidAlignment, Position{File: name}, err)
+		}
 	}
 
 	// Lowercase the types of background.
@@ -86,9 +314,121 @@ func (u Universe) FindCoster(upgrade Upgrade) (Coster, bool) {
 		return spell, true
 	}
 
+	elite, found := u.FindElite(upgrade)
+	if found {
+		return elite, true
+	}
+
+	condition, found := u.FindCondition(upgrade)
+	if found {
+		return condition, true
+	}
+
+	rule, found := u.FindRule(upgrade)
+	if found {
+		return rule, true
+	}
+
 	return nil, false
 }
 
+// FindRule returns the explicit rule corresponding to the given label or a zero value, and a boolean indicating if it was found.
+func (u Universe) FindRule(upgrade Upgrade) (Rule, bool) {
+
+	// Explicit rules upgrades are defined by the rule prefix, followed by the
+	// name of the rule.
+	// Examples: Rule: Fear of the Dark
+	fields := strings.SplitN(upgrade.Name, ":", 2)
+	if len(fields) != 2 || !strings.EqualFold(strings.TrimSpace(fields[0]), RulePrefix) {
+		return Rule{}, false
+	}
+
+	name := strings.TrimSpace(fields[1])
+	if len(name) == 0 {
+		return Rule{}, false
+	}
+
+	for _, rule := range u.Rules {
+		if strings.EqualFold(rule.Name, name) {
+			return rule, true
+		}
+	}
+
+	return Rule{
+		Name: name,
+	}, true
+}
+
+// Suggest returns the names of the universe's entries closest to the given
+// upgrade, by edit distance and ignoring the case, from the closest to the
+// farthest.
+func (u Universe) Suggest(upgrade Upgrade) []string {
+
+	// Compare the label without its speciality nor value.
+	fields := strings.Fields(strings.SplitN(upgrade.Name, ":", 2)[0])
+	if len(fields) > 1 {
+		if _, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			fields = fields[:len(fields)-1]
+		}
+	}
+	name := strings.Join(fields, " ")
+
+	candidates := []string{}
+	for _, c := range u.Characteristics {
+		candidates = append(candidates, c.Name)
+	}
+	for _, s := range u.Skills {
+		candidates = append(candidates, s.Name)
+	}
+	for _, t := range u.Talents {
+		candidates = append(candidates, t.Name)
+	}
+	for _, a := range u.Aptitudes {
+		candidates = append(candidates, string(a))
+	}
+	for _, g := range u.Gauges {
+		candidates = append(candidates, g.Name)
+	}
+	for _, s := range u.Spells {
+		candidates = append(candidates, s.Name)
+	}
+	for _, e := range u.Elites {
+		candidates = append(candidates, e.Name)
+	}
+
+	// The special rules are never suggested: they are always found with the
+	// rule prefix, and never without.
+
+	// Keep the candidates close enough to be a typo.
+	threshold := len([]rune(name))/3 + 1
+	distances := make(map[string]int)
+	suggestions := []string{}
+	for _, candidate := range candidates {
+		d := distance(name, candidate)
+		if d > threshold {
+			continue
+		}
+		if _, found := distances[candidate]; found {
+			continue
+		}
+		distances[candidate] = d
+		suggestions = append(suggestions, candidate)
+	}
+
+	slice.Sort(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+
+	if len(suggestions) > 3 {
+		suggestions = suggestions[:3]
+	}
+
+	return suggestions
+}
+
 // FindCharacteristic returns the characteristic correponding to the given label or a zero-value, and a boolean indicating if it was found.
 func (u Universe) FindCharacteristic(upgrade Upgrade) (Characteristic, bool) {
 
@@ -169,13 +509,29 @@ func (u Universe) FindGauge(upgrade Upgrade) (Gauge, bool) {
 	// Gauges upgrades are defined by a name and a value, separated by a space.
 	fields := split(upgrade.Name, ' ')
 
+	if len(fields) < 2 {
+		return Gauge{}, false
+	}
+	name := strings.Join(fields[:len(fields)-1], " ")
+
 	for _, gauge := range u.Gauges {
-		if strings.EqualFold(gauge.Name, fields[0]) {
-			val, err := strconv.Atoi(fields[1])
+		track, found := "", strings.EqualFold(gauge.Name, name)
+
+		// The tracks are upgraded by the name of the gauge followed by the
+		// name of the track.
+		for _, t := range gauge.Tracks {
+			if strings.EqualFold(gauge.Name+" "+t.Name, name) {
+				track, found = t.Name, true
+			}
+		}
+
+		if found {
+			val, err := strconv.Atoi(fields[len(fields)-1])
 			if err != nil {
 				panic(err)
 			}
 			gauge.Value = val
+			gauge.track = track
 			return gauge, true
 		}
 	}
@@ -211,3 +567,16 @@ func (u Universe) FindSpell(upgrade Upgrade) (Spell, bool) {
 
 	return Spell{}, false
 }
+
+// FindElite returns the elite advance corresponding to the given label or a
+// zero value, and a boolean indicating if it was found.
+func (u Universe) FindElite(upgrade Upgrade) (Elite, bool) {
+
+	for _, elite := range u.Elites {
+		if strings.EqualFold(elite.Name, upgrade.Name) {
+			return elite, true
+		}
+	}
+
+	return Elite{}, false
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Universe_FindRule(t *testing.T) {
+	universe := Universe{
+		Rules: []Rule{
+			{Name: "Brute", Description: "Hits hard"},
+		},
+	}
+
+	cases := []struct {
+		in    string
+		out   Rule
+		found bool
+	}{
+		{
+			in:    "Fear of the Dark",
+			out:   Rule{},
+			found: false,
+		},
+		{
+			in:    "Rule:",
+			out:   Rule{},
+			found: false,
+		},
+		{
+			in:    "Rule: Fear of the Dark",
+			out:   Rule{Name: "Fear of the Dark"},
+			found: true,
+		},
+		{
+			in:    "rule:Fear of the Dark",
+			out:   Rule{Name: "Fear of the Dark"},
+			found: true,
+		},
+		{
+			in:    "brute",
+			out:   Rule{},
+			found: false,
+		},
+		{
+			in:    "Rule: Brute",
+			out:   Rule{Name: "Brute", Description: "Hits hard"},
+			found: true,
+		},
+	}
+
+	for i, c := range cases {
+		out, found := universe.FindRule(Upgrade{Name: c.in})
+		if found != c.found || !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v %t", c.out, c.found)
+			t.Logf("	Having %v %t", out, found)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Universe_Suggest(t *testing.T) {
+	universe := Universe{
+		Characteristics: []Characteristic{
+			{Name: "STR"},
+			{Name: "TOU"},
+		},
+		Skills: []Skill{
+			{Name: "Awareness"},
+			{Name: "Common Lore"},
+			{Name: "Forbidden Lore"},
+		},
+		Talents: []Talent{
+			{Name: "Iron Jaw"},
+		},
+		Rules: []Rule{
+			{Name: "Iron Jaws"},
+		},
+	}
+
+	cases := []struct {
+		in  string
+		out []string
+	}{
+		{
+			in:  "Awarness",
+			out: []string{"Awareness"},
+		},
+		{
+			in:  "awareness",
+			out: []string{"Awareness"},
+		},
+		{
+			in:  "Comon Lore: Imperium",
+			out: []string{"Common Lore"},
+		},
+		{
+			in:  "STT +5",
+			out: []string{"STR"},
+		},
+		{
+			in:  "Lore",
+			out: []string{},
+		},
+		{
+			in:  "Iron Jw",
+			out: []string{"Iron Jaw"},
+		},
+	}
+
+	for i, c := range cases {
+		out := universe.Suggest(Upgrade{Name: c.in})
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_parseUniverse_Errors(t *testing.T) {
+	cases := []struct {
+		in   string
+		code ErrorCode
+		line int
+		msg  string
+	}{
+		{
+			in: `skills:
+  - name: Awareness
+    aptitudes: [Perception]
+  - name: Dodge
+    aptitudes: 3
+talents:
+  - tier: one
+    name: Catfall
+`,
+			code: InvalidUniverse,
+			line: 5,
+			msg: "universe.yaml:5: skills entry Dodge: cannot unmarshal !!int `3` into []main.Aptitude\n" +
+				"universe.yaml:7: talents entry Catfall: cannot unmarshal !!str `one` into int",
+		},
+		{
+			in: `skills:
+  - name: Awareness
+    aptitudes: [Perception
+`,
+			code: InvalidUniverse,
+			line: 3,
+			msg:  "universe.yaml:3: skills entry Awareness: did not find expected ',' or ']'",
+		},
+		{
+			in: `skills:
+  - aptitudes: [Perception]
+gauges:
+  - max: 3
+`,
+			code: UnnamedUniverseEntry,
+			msg:  "universe.yaml: the gauge entry #1 has no name",
+		},
+	}
+
+	for i, c := range cases {
+		_, err := parseUniverse(strings.NewReader(c.in), "universe.yaml")
+		e, ok := err.(Error)
+		if !ok {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+			continue
+		}
+		position, _ := e.Position()
+		if e.Code != c.code || position.Line != c.line || (len(c.msg) != 0 && e.Error() != c.msg) {
+			t.Logf("Unexpected error on case %d: %s", i+1, err)
+			t.Fail()
+		}
+	}
+}
+
+func Test_LoadUniverse_Generation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adeptus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	// The background of the second file relies on the dice of the first.
+	files := map[string]string{
+		"a.yaml": "generation: {method: roll, base: 20, dice: 2d10}\n",
+		"b.yaml": "backgrounds:\n  homeworld:\n    - name: Feral World\n      generation: {method: roll, base: 25}\n",
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	universe, err := LoadUniverse(dir)
+	if err != nil {
+		t.Logf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	generation := universe.GenerationOf(universe.Backgrounds["homeworld"])
+	if generation.Dice != "2d10" || generation.Base != 25 {
+		t.Logf("Unexpected generation: %v", generation)
+		t.Fail()
+	}
+}
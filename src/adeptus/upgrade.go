@@ -34,6 +34,7 @@ type Upgrade struct {
 	Name string
 	Cost *int
 	Line int
+	File string
 }
 
 // parseUpgrade generate an upgrade from a raw line. The line must not be empty.
@@ -48,12 +49,12 @@ func parseUpgrade(line line) (Upgrade, error) {
 
 	// The minimum number of fields is 2
 	if len(fields) < 2 {
-		return Upgrade{}, NewError(InvalidUpgradeFormat, line.Number)
+		return Upgrade{}, NewError(InvalidUpgradeFormat, line.Position())
 	}
 
 	// Parse the mark
 	if !in(fields[0], marks) {
-		return Upgrade{}, NewError(InvalidUpgradeMark, line.Number)
+		return Upgrade{}, NewError(InvalidUpgradeMark, line.Locate(fields[0]))
 	}
 	mark := fields[0]
 
@@ -66,7 +67,7 @@ func parseUpgrade(line line) (Upgrade, error) {
 		// If one end has the brackets but not the other, that's an error:
 		// brackets does by pairs, and are forbidden in the title
 		if strings.HasPrefix(field, "[") != strings.HasSuffix(field, "]") {
-			return Upgrade{}, NewError(InvalidUpgradeCost, line.Number)
+			return Upgrade{}, NewError(InvalidUpgradeCost, line.Locate(field))
 		}
 
 		// If the brackets are absents, that's not a cost, so skip the field.
@@ -78,12 +79,12 @@ func parseUpgrade(line line) (Upgrade, error) {
 
 		// There can be only one cost on the line
 		if cost != nil {
-			return Upgrade{}, NewError(DuplicateUpgradeCost, line.Number)
+			return Upgrade{}, NewError(DuplicateUpgradeCost, line.Locate(field))
 		}
 
 		// Check position of the cost
 		if i != 0 && i != len(fields)-1 {
-			return Upgrade{}, NewError(ForbidenCostPosition, line.Number)
+			return Upgrade{}, NewError(ForbidenCostPosition, line.Locate(field))
 		}
 
 		// Trim the field to get the raw cost
@@ -92,12 +93,12 @@ func parseUpgrade(line line) (Upgrade, error) {
 		// Parse the cost
 		c, err := strconv.Atoi(raw)
 		if err != nil {
-			return Upgrade{}, NewError(InvalidUpgradeCost, line.Number)
+			return Upgrade{}, NewError(InvalidUpgradeCost, line.Locate(field))
 		}
 
 		// Check the cost is positive
 		if c < 0 {
-			return Upgrade{}, NewError(InvalidUpgradeCost, line.Number)
+			return Upgrade{}, NewError(InvalidUpgradeCost, line.Locate(field))
 		}
 		cost = &c
 
@@ -107,7 +108,7 @@ func parseUpgrade(line line) (Upgrade, error) {
 
 	// The remaining line is the name of the upgrade
 	if len(fields) == 0 {
-		return Upgrade{}, NewError(EmptyUpgrade, line.Number)
+		return Upgrade{}, NewError(EmptyUpgrade, line.Position())
 	}
 
 	// In case of non apply mark, the default cost value is 0.
@@ -120,16 +121,25 @@ func parseUpgrade(line line) (Upgrade, error) {
 		Name: strings.Join(fields, " "),
 		Cost: cost,
 		Line: line.Number,
+		File: line.File,
 	}, nil
 }
 
+// Position returns the position of the upgrade in the sheet.
+func (u Upgrade) Position() Position {
+	return Position{
+		File: u.File,
+		Line: u.Line,
+	}
+}
+
 // Split returns the name and speciality of an upgrade.
 func (u Upgrade) Split() (string, string, error) {
 
 	// Check if the skill has a speciality
 	splits := strings.Split(u.Name, ":")
 	if len(splits) > 2 {
-		return "", "", NewError(InvalidUpgradeFormat, u.Line)
+		return "", "", NewError(InvalidUpgradeFormat, u.Position())
 	}
 
 	// Get name.
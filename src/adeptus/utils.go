@@ -18,7 +18,65 @@ func split(s string, c rune) []string {
 	})
 }
 
+// splitOutside slices s into all substrings separated by c, ignoring the
+// separators enclosed in parenthesis.
+func splitOutside(s string, c rune) []string {
+	fields := []string{}
+	depth, start := 0, 0
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == c && depth == 0:
+			fields = append(fields, s[start:i])
+			start = i + len(string(r))
+		}
+	}
+	return append(fields, s[start:])
+}
+
 // IntP returns the pointer to the given var
 func IntP(v int) *int {
 	return &v
 }
+
+// distance returns the Levenshtein distance between the two strings, ignoring
+// the case.
+func distance(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+
+	// Compute the distance row by row, keeping only the previous one.
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+
+	return previous[len(rb)]
+}
+
+// min returns the smallest of the given integers.
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
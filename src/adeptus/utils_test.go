@@ -52,3 +52,52 @@ func Test_in(t *testing.T) {
 		}
 	}
 }
+
+func Test_distance(t *testing.T) {
+	cases := []struct {
+		a   string
+		b   string
+		out int
+	}{
+		{
+			a:   "",
+			b:   "",
+			out: 0,
+		},
+		{
+			a:   "Awareness",
+			b:   "",
+			out: 9,
+		},
+		{
+			a:   "Awareness",
+			b:   "awareness",
+			out: 0,
+		},
+		{
+			a:   "Awarness",
+			b:   "Awareness",
+			out: 1,
+		},
+		{
+			a:   "Dodge",
+			b:   "Awareness",
+			out: 8,
+		},
+		{
+			a:   "Précision",
+			b:   "Precision",
+			out: 1,
+		},
+	}
+
+	for i, c := range cases {
+		out := distance(c.a, c.b)
+		if out != c.out {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("Expected %d", c.out)
+			t.Logf("Having %d", out)
+			t.Fail()
+		}
+	}
+}
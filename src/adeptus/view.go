@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bradfitz/slice"
+)
+
+// View is the compiled character with its traits sorted for display. It is
+// the data given to the templates.
+type View struct {
+	Name        string
+	Backgrounds []Background
+	Career      string
+	Rank        string
+	Alignment   string
+	Aptitudes   []Aptitude
+	Experience  int
+	Spent       int
+
+	Characteristics []CharacteristicView
+	Gauges          []GaugeView
+	Skills          []SkillView
+	Talents         []Talent
+	Spells          []Spell
+	Elites          []Elite
+	Conditions      []Effect
+	Rules           []Rule
+	Modifiers       []Source
+	Crossings       []Crossing
+
+	History  []Record
+	Balances []Balance
+
+	// Suggestions are the purchasable upgrades, given only to the suggest
+	// command's templates.
+	Suggestions []Upgrade
+}
+
+// CharacteristicView is a characteristic along with its modifiers.
+type CharacteristicView struct {
+	Characteristic
+
+	// Modifier is the sum of the untagged modifiers of the characteristic.
+	Modifier int
+
+	// Effective is the value of the characteristic with its modifiers.
+	Effective int
+
+	// Bonus is the effective bonus of the characteristic.
+	Bonus int
+
+	// Modified is true if the modifiers change the value or the bonus.
+	Modified bool
+}
+
+// GaugeView is a gauge along with its degraded tracks and modifiers.
+type GaugeView struct {
+	Gauge
+
+	// Modifier is the sum of the untagged modifiers of the gauge.
+	Modifier int
+
+	// Effective is the current value of the gauge with its modifiers.
+	Effective int
+
+	// Degradations lists the degraded tracks, like "Fatigue 2".
+	Degradations []string
+
+	// Modified is true if the tracks or the modifiers change the value.
+	Modified bool
+}
+
+// SkillView is a skill along with its modifiers.
+type SkillView struct {
+	Skill
+
+	// Bonus is the bonus given by the tier of the skill.
+	Bonus int
+
+	// Modifier is the sum of the untagged modifiers of the skill.
+	Modifier int
+
+	// Effective is the bonus of the skill with its modifiers.
+	Effective int
+}
+
+// trainings are the names of the skills' tiers.
+var trainings = []string{"Known", "Trained", "Experienced", "Veteran"}
+
+// Training returns the name of the skill's tier, or its bonus beyond the named
+// tiers.
+func (s SkillView) Training() string {
+	if s.Tier >= 1 && s.Tier <= len(trainings) {
+		return trainings[s.Tier-1]
+	}
+	return fmt.Sprintf("%+d", s.Bonus)
+}
+
+// Remaining returns the experience left to spend.
+func (v View) Remaining() int {
+	return v.Experience - v.Spent
+}
+
+// CharacteristicSum returns the sum of the characteristics' values.
+func (v View) CharacteristicSum() int {
+	sum := 0
+	for _, characteristic := range v.Characteristics {
+		sum += characteristic.Value
+	}
+	return sum
+}
+
+// Filter returns the view limited to the records selected by the filter: the
+// history and the balances of the sessions keep only the selected records,
+// and the sessions without any are dropped unless only the dates are
+// filtered.
+func (v View) Filter(filter Filter) View {
+	history := []Record{}
+	if filter.MatchBackgrounds() {
+		for _, record := range v.History {
+			if len(record.Background) != 0 && filter.MatchRecord(record) {
+				history = append(history, record)
+			}
+		}
+	}
+
+	balances := []Balance{}
+	for _, balance := range v.Balances {
+		if !filter.MatchSession(balance.Session) {
+			continue
+		}
+
+		records := []Record{}
+		for _, record := range balance.Upgrades {
+			if filter.MatchRecord(record) {
+				records = append(records, record)
+			}
+		}
+
+		if len(records) == 0 && (len(filter.Types) != 0 || len(filter.Name) != 0) {
+			continue
+		}
+
+		balance.Upgrades = records
+		balances = append(balances, balance)
+		history = append(history, records...)
+	}
+
+	v.History = history
+	v.Balances = balances
+	return v
+}
+
+// View returns the sorted view of the character.
+func (c *Character) View() View {
+	v := View{
+		Name:       c.Name,
+		Career:     c.Career,
+		Rank:       c.Rank,
+		Alignment:  c.Alignment,
+		Experience: c.Experience,
+		Spent:      c.Spent,
+		Modifiers:  c.Sources(),
+		Crossings:  c.Crossings,
+		History:    c.History,
+		Balances:   c.Balances,
+	}
+
+	for _, background := range c.Backgrounds {
+		v.Backgrounds = append(v.Backgrounds, background)
+	}
+	slice.Sort(v.Backgrounds, func(i, j int) bool {
+		if v.Backgrounds[i].Type != v.Backgrounds[j].Type {
+			return v.Backgrounds[i].Type < v.Backgrounds[j].Type
+		}
+		return v.Backgrounds[i].Name < v.Backgrounds[j].Name
+	})
+
+	for _, aptitude := range c.Aptitudes {
+		v.Aptitudes = append(v.Aptitudes, aptitude)
+	}
+	slice.Sort(v.Aptitudes, func(i, j int) bool {
+		return v.Aptitudes[i] < v.Aptitudes[j]
+	})
+
+	for _, characteristic := range c.Characteristics {
+		modifier, bonus := c.Modifier(characteristic.Name), c.Bonus(characteristic.Name)
+		v.Characteristics = append(v.Characteristics, CharacteristicView{
+			Characteristic: characteristic,
+			Modifier:       modifier,
+			Effective:      characteristic.Value + modifier,
+			Bonus:          bonus,
+			Modified:       modifier != 0 || bonus != characteristic.Value/10,
+		})
+	}
+	slice.Sort(v.Characteristics, func(i, j int) bool {
+		return v.Characteristics[i].Name < v.Characteristics[j].Name
+	})
+
+	for _, gauge := range c.Gauges {
+		modifier := c.Modifier(gauge.Name)
+		degradations := []string{}
+		for _, track := range gauge.Tracks {
+			if gauge.Degradation[track.Name] != 0 {
+				degradations = append(degradations, fmt.Sprintf("%s %d", track.Name, gauge.Degradation[track.Name]))
+			}
+		}
+		v.Gauges = append(v.Gauges, GaugeView{
+			Gauge:        gauge,
+			Modifier:     modifier,
+			Effective:    gauge.Current() + modifier,
+			Degradations: degradations,
+			Modified:     gauge.Degraded() != 0 || modifier != 0,
+		})
+	}
+	slice.Sort(v.Gauges, func(i, j int) bool {
+		return v.Gauges[i].Name < v.Gauges[j].Name
+	})
+
+	for _, skill := range c.Skills {
+		modifier := c.Modifier(skill.FullName())
+		v.Skills = append(v.Skills, SkillView{
+			Skill:     skill,
+			Bonus:     (skill.Tier - 1) * 10,
+			Modifier:  modifier,
+			Effective: (skill.Tier-1)*10 + modifier,
+		})
+	}
+	slice.Sort(v.Skills, func(i, j int) bool {
+		return v.Skills[i].FullName() < v.Skills[j].FullName()
+	})
+
+	for _, talent := range c.Talents {
+		v.Talents = append(v.Talents, talent)
+	}
+	slice.Sort(v.Talents, func(i, j int) bool {
+		return v.Talents[i].FullName() < v.Talents[j].FullName()
+	})
+
+	for _, spell := range c.Spells {
+		v.Spells = append(v.Spells, spell)
+	}
+	slice.Sort(v.Spells, func(i, j int) bool {
+		return v.Spells[i].Name < v.Spells[j].Name
+	})
+
+	for _, elite := range c.Elites {
+		v.Elites = append(v.Elites, elite)
+	}
+	slice.Sort(v.Elites, func(i, j int) bool {
+		return v.Elites[i].Name < v.Elites[j].Name
+	})
+
+	for _, effect := range c.Conditions {
+		v.Conditions = append(v.Conditions, effect)
+	}
+	slice.Sort(v.Conditions, func(i, j int) bool {
+		return v.Conditions[i].Condition.Name < v.Conditions[j].Condition.Name
+	})
+
+	for _, rule := range c.Rules {
+		v.Rules = append(v.Rules, rule)
+	}
+	slice.Sort(v.Rules, func(i, j int) bool {
+		return v.Rules[i].Name < v.Rules[j].Name
+	})
+
+	return v
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Character_View(t *testing.T) {
+	c := Character{
+		Characteristics: map[string]Characteristic{
+			"WS":  {Name: "WS", Value: 35},
+			"AGI": {Name: "AGI", Value: 40},
+		},
+		Skills: map[string]Skill{
+			"Dodge":     {Name: "Dodge", Tier: 2},
+			"Awareness": {Name: "Awareness", Tier: 1},
+		},
+		Talents: map[string]Talent{
+			"Catfall": {Name: "Catfall", Value: 1, Modifiers: []Modifier{{Target: "AGI", Value: 5}, {Target: "Dodge", Value: 10}}},
+		},
+		Experience: 1000,
+		Spent:      300,
+	}
+
+	v := c.View()
+
+	characteristics := []CharacteristicView{
+		{Characteristic: Characteristic{Name: "AGI", Value: 40}, Modifier: 5, Effective: 45, Bonus: 4, Modified: true},
+		{Characteristic: Characteristic{Name: "WS", Value: 35}, Effective: 35, Bonus: 3},
+	}
+	if !reflect.DeepEqual(v.Characteristics, characteristics) {
+		t.Logf("Unexpected characteristics:")
+		t.Logf("	Expected %v", characteristics)
+		t.Logf("	Having %v", v.Characteristics)
+		t.Fail()
+	}
+
+	skills := []SkillView{
+		{Skill: Skill{Name: "Awareness", Tier: 1}},
+		{Skill: Skill{Name: "Dodge", Tier: 2}, Bonus: 10, Modifier: 10, Effective: 20},
+	}
+	if !reflect.DeepEqual(v.Skills, skills) {
+		t.Logf("Unexpected skills:")
+		t.Logf("	Expected %v", skills)
+		t.Logf("	Having %v", v.Skills)
+		t.Fail()
+	}
+
+	if v.Remaining() != 700 || v.CharacteristicSum() != 75 {
+		t.Logf("Unexpected totals: %d remaining, %d characteristics", v.Remaining(), v.CharacteristicSum())
+		t.Fail()
+	}
+}
+
+func Test_View_Filter(t *testing.T) {
+	background := Record{Upgrade: Upgrade{Mark: MarkApply, Name: "Awareness"}, Type: "skill", Background: "Seeker"}
+	dodge := Record{Upgrade: Upgrade{Mark: MarkApply, Name: "Dodge"}, Type: "skill"}
+	catfall := Record{Upgrade: Upgrade{Mark: MarkApply, Name: "Catfall"}, Type: "talent"}
+
+	v := View{
+		History: []Record{background, dodge, catfall},
+		Balances: []Balance{
+			{Session: Session{Title: "Creation"}, Upgrades: []Record{dodge}},
+			{Session: Session{Title: "First"}, Upgrades: []Record{catfall}},
+		},
+	}
+
+	cases := []struct {
+		filter   Filter
+		history  []Record
+		sessions []string
+	}{
+		{
+			filter:   Filter{},
+			history:  []Record{background, dodge, catfall},
+			sessions: []string{"Creation", "First"},
+		},
+		{
+			filter:   Filter{Types: []string{"skill"}},
+			history:  []Record{background, dodge},
+			sessions: []string{"Creation"},
+		},
+		{
+			filter:   Filter{Name: "cat"},
+			history:  []Record{catfall},
+			sessions: []string{"First"},
+		},
+	}
+
+	for i, c := range cases {
+		out := v.Filter(c.filter)
+		sessions := []string{}
+		for _, balance := range out.Balances {
+			sessions = append(sessions, balance.Session.Title)
+		}
+		if !reflect.DeepEqual(out.History, c.history) || !reflect.DeepEqual(sessions, c.sessions) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v %v", c.history, c.sessions)
+			t.Logf("	Having %v %v", out.History, sessions)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/bradfitz/slice"
+	"gopkg.in/yaml.v2"
+)
+
+// ExportVTT is the format of the actors of the virtual tabletops, in JSON.
+const ExportVTT = "vtt"
+
+// MappingDir is the directory of the universe holding the mappings of the
+// VTT systems, one YAML file per system.
+const MappingDir = "vtt"
+
+// Properties maps the properties of a trait to the paths of the actor
+// receiving them. A path is made of keys separated by dots, a key like
+// attribs[WS] selecting the object named WS of the list attribs.
+type Properties map[string]string
+
+// ItemMapping maps a trait to an item of the actor.
+type ItemMapping struct {
+	// Document is the item completed with the trait's properties.
+	Document   map[string]interface{} `yaml:"document"`
+	Properties Properties             `yaml:"properties"`
+}
+
+// Mapping maps the compiled character to the actor document of a VTT system.
+type Mapping struct {
+	// Document is the actor completed with the character.
+	Document map[string]interface{} `yaml:"actor"`
+
+	Name            string                `yaml:"name"`
+	Experience      Properties            `yaml:"experience"`
+	Characteristics map[string]Properties `yaml:"characteristics"`
+	Skills          map[string]Properties `yaml:"skills"`
+	Gauges          map[string]Properties `yaml:"gauges"`
+
+	// Items is the path of the list of items of the actor.
+	Items   string       `yaml:"items"`
+	Talents *ItemMapping `yaml:"talents"`
+	Spells  *ItemMapping `yaml:"spells"`
+	Rules   *ItemMapping `yaml:"rules"`
+}
+
+// The properties available for each kind of trait. The spells' properties
+// also include their attributes.
+var (
+	experienceProperties     = []string{"total", "spent", "remaining"}
+	characteristicProperties = []string{"value", "start", "increase", "advances", "bonus", "effective"}
+	skillProperties          = []string{"advances", "bonus", "training", "effective"}
+	gaugeProperties          = []string{"value", "current"}
+	talentProperties         = []string{"name", "description", "value"}
+	spellProperties          = []string{"name", "description", "xp"}
+	ruleProperties           = []string{"name", "description"}
+)
+
+// Check returns an error if the mapping uses a property that doesn't exist,
+// the spells' attributes being those of the universe.
+func (m Mapping) Check(universe Universe) error {
+	check := func(kind string, properties Properties, available []string) error {
+		for property := range properties {
+			if !in(property, available) {
+				return fmt.Errorf("the %s property %s doesn't exist, only %s", kind, property, strings.Join(available, ", "))
+			}
+		}
+		return nil
+	}
+
+	err := check("experience", m.Experience, experienceProperties)
+	if err != nil {
+		return err
+	}
+	for _, properties := range m.Characteristics {
+		err = check("characteristic", properties, characteristicProperties)
+		if err != nil {
+			return err
+		}
+	}
+	for _, properties := range m.Skills {
+		err = check("skill", properties, skillProperties)
+		if err != nil {
+			return err
+		}
+	}
+	for _, properties := range m.Gauges {
+		err = check("gauge", properties, gaugeProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if m.Talents != nil {
+		err = check("talent", m.Talents.Properties, talentProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if m.Spells != nil {
+		available := append([]string{}, spellProperties...)
+		for _, spell := range universe.Spells {
+			for name := range spell.Attributes {
+				if !in(name, available) {
+					available = append(available, name)
+				}
+			}
+		}
+		err = check("spell", m.Spells.Properties, available)
+		if err != nil {
+			return err
+		}
+	}
+	if m.Rules != nil {
+		err = check("rule", m.Rules.Properties, ruleProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if (m.Talents != nil || m.Spells != nil || m.Rules != nil) && len(m.Items) == 0 {
+		return fmt.Errorf("the path of the items is missing")
+	}
+	return nil
+}
+
+// LoadMapping returns the mapping of the given VTT system from the universe
+// directory. Without a system, the only mapping of the directory is used.
+func LoadMapping(dir string, system string, universe Universe) (Mapping, error) {
+	path := filepath.Join(dir, MappingDir, system+".yaml")
+	if len(system) == 0 {
+		files, err := filepath.Glob(filepath.Join(dir, MappingDir, "*.yaml"))
+		if err != nil {
+			return Mapping{}, err
+		}
+		if len(files) != 1 {
+			systems := []string{}
+			for _, f := range files {
+				systems = append(systems, strings.TrimSuffix(filepath.Base(f), ".yaml"))
+			}
+			return Mapping{}, fmt.Errorf("choose the VTT system between %s", strings.Join(systems, ", "))
+		}
+		path = files[0]
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Mapping{}, err
+	}
+	var m Mapping
+	err = yaml.Unmarshal(raw, &m)
+	if err != nil {
+		return Mapping{}, NewError(InvalidMapping, Position{File: path}, err)
+	}
+	err = m.Check(universe)
+	if err != nil {
+		return Mapping{}, NewError(InvalidMapping, Position{File: path}, err)
+	}
+	return m, nil
+}
+
+// Actor returns the actor document of the character.
+func (m Mapping) Actor(v View) (map[string]interface{}, error) {
+	actor, _ := jsonable(m.Document).(map[string]interface{})
+	if actor == nil {
+		actor = make(map[string]interface{})
+	}
+
+	var err error
+	set := func(path string, value interface{}) {
+		if err == nil && len(path) != 0 {
+			err = setPath(actor, path, value)
+		}
+	}
+
+	set(m.Name, v.Name)
+	set(m.Experience["total"], v.Experience)
+	set(m.Experience["spent"], v.Spent)
+	set(m.Experience["remaining"], v.Remaining())
+
+	for _, characteristic := range v.Characteristics {
+		properties := m.Characteristics[characteristic.Name]
+		set(properties["value"], characteristic.Value)
+		set(properties["start"], characteristic.Value-characteristic.Advance)
+		set(properties["increase"], characteristic.Advance)
+		set(properties["advances"], characteristic.Tier)
+		set(properties["bonus"], characteristic.Bonus)
+		set(properties["effective"], characteristic.Effective)
+	}
+
+	for _, skill := range v.Skills {
+		properties, found := m.Skills[skill.FullName()]
+		if !found {
+			properties = m.Skills[skill.Name]
+		}
+		// The properties are set in a fixed order, which is the order of the
+		// objects appended to the lists.
+		for _, property := range skillProperties {
+			path, found := properties[property]
+			if !found {
+				continue
+			}
+			path = strings.Replace(path, "{speciality}", anchor(skill.Speciality), -1)
+			switch property {
+			case "advances":
+				set(path, skill.Tier)
+			case "bonus":
+				set(path, skill.Bonus)
+			case "training":
+				set(path, skill.Training())
+			case "effective":
+				set(path, skill.Effective)
+			}
+		}
+	}
+
+	for _, gauge := range v.Gauges {
+		properties := m.Gauges[gauge.Name]
+		set(properties["value"], gauge.Value)
+		set(properties["current"], gauge.Effective)
+	}
+
+	// The talents, spells and rules are items, added after those of the
+	// actor document, if any.
+	if err != nil {
+		return nil, err
+	}
+	items, _ := getPath(actor, m.Items).([]interface{})
+	item := func(mapping *ItemMapping, properties map[string]interface{}) error {
+		document, _ := jsonable(mapping.Document).(map[string]interface{})
+		if document == nil {
+			document = make(map[string]interface{})
+		}
+		names := []string{}
+		for property := range mapping.Properties {
+			names = append(names, property)
+		}
+		slice.Sort(names, func(i, j int) bool {
+			return names[i] < names[j]
+		})
+		for _, property := range names {
+			err := setPath(document, mapping.Properties[property], properties[property])
+			if err != nil {
+				return err
+			}
+		}
+		items = append(items, document)
+		return nil
+	}
+
+	if m.Talents != nil {
+		for _, talent := range v.Talents {
+			err = item(m.Talents, map[string]interface{}{"name": title(talent.FullName()), "description": talent.Description, "value": talent.Value})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if m.Spells != nil {
+		for _, spell := range v.Spells {
+			properties := map[string]interface{}{"name": title(spell.Name), "description": spell.Description, "xp": spell.XP}
+			for name, value := range spell.Attributes {
+				if !in(name, spellProperties) {
+					properties[name] = jsonable(value)
+				}
+			}
+			err = item(m.Spells, properties)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if m.Rules != nil {
+		for _, rule := range v.Rules {
+			err = item(m.Rules, map[string]interface{}{"name": title(rule.Name), "description": rule.Description})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if items != nil {
+		err = setPath(actor, m.Items, items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return actor, nil
+}
+
+// RenderVTT writes the actor document of the character for the given VTT
+// system, whose mapping is read from the universe directory.
+func RenderVTT(w io.Writer, c *Character, universe Universe, dir string, system string) error {
+	m, err := LoadMapping(dir, system, universe)
+	if err != nil {
+		return err
+	}
+	actor, err := m.Actor(c.View())
+	if err != nil {
+		return err
+	}
+	// The actor is imported as is, without escaping the names for HTML.
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(actor)
+}
+
+// splitKey returns the key of a path's step, and the name of the object of
+// the list it selects, if any.
+func splitKey(step string) (string, string, bool) {
+	open := strings.Index(step, "[")
+	if open < 0 || !strings.HasSuffix(step, "]") {
+		return step, "", false
+	}
+	return step[:open], step[open+1 : len(step)-1], true
+}
+
+// setPath sets the value at the given path of the document, creating the
+// missing objects on the way.
+func setPath(document map[string]interface{}, path string, value interface{}) error {
+	steps := strings.Split(path, ".")
+	current := document
+	for i, step := range steps {
+		key, name, selects := splitKey(step)
+		last := i == len(steps)-1
+
+		if !selects {
+			if last {
+				current[key] = value
+				return nil
+			}
+			next, found := current[key]
+			if !found {
+				next = make(map[string]interface{})
+				current[key] = next
+			}
+			object, ok := next.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s isn't an object in %s", key, path)
+			}
+			current = object
+			continue
+		}
+
+		// Select the named object of the list, appending it if missing.
+		list, ok := current[key].([]interface{})
+		if !ok && current[key] != nil {
+			return fmt.Errorf("%s isn't a list in %s", key, path)
+		}
+		var object map[string]interface{}
+		for _, element := range list {
+			if e, ok := element.(map[string]interface{}); ok && e["name"] == name {
+				object = e
+				break
+			}
+		}
+		if object == nil {
+			object = map[string]interface{}{"name": name}
+			current[key] = append(list, object)
+		}
+		if last {
+			return fmt.Errorf("%s selects an object in %s", step, path)
+		}
+		current = object
+	}
+	return nil
+}
+
+// getPath returns the value at the given path of the document, or nil.
+func getPath(document map[string]interface{}, path string) interface{} {
+	var current interface{} = document
+	for _, step := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		key, name, selects := splitKey(step)
+		current = object[key]
+		if !selects {
+			continue
+		}
+
+		// Select the named object of the list.
+		list, _ := current.([]interface{})
+		current = nil
+		for _, element := range list {
+			if e, ok := element.(map[string]interface{}); ok && e["name"] == name {
+				current = e
+				break
+			}
+		}
+	}
+	return current
+}
+
+// jsonable returns a deep copy of the YAML value which can be written as
+// JSON, the keys of its objects being strings.
+func jsonable(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{})
+		for k, v := range value {
+			object[fmt.Sprint(k)] = jsonable(v)
+		}
+		return object
+	case map[string]interface{}:
+		object := make(map[string]interface{})
+		for k, v := range value {
+			object[k] = jsonable(v)
+		}
+		return object
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, v := range value {
+			list[i] = jsonable(v)
+		}
+		return list
+	}
+	return value
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// conform returns an error if the document has a key absent from the sample
+// document, or a value of another type. Each element of a list must conform
+// to one of the elements of the sample's list.
+func conform(document interface{}, sample interface{}, path string) error {
+	if document == nil {
+		return nil
+	}
+	switch document := document.(type) {
+	case map[string]interface{}:
+		object, ok := sample.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s isn't an object", path)
+		}
+		for key, value := range document {
+			if _, found := object[key]; !found {
+				return fmt.Errorf("%s.%s isn't in the schema", path, key)
+			}
+			err := conform(value, object[key], path+"."+key)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		list, ok := sample.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s isn't a list", path)
+		}
+		for i, element := range document {
+			var err error
+			for _, s := range list {
+				err = conform(element, s, fmt.Sprintf("%s[%d]", path, i))
+				if err == nil {
+					break
+				}
+			}
+			if err != nil || len(list) == 0 {
+				return fmt.Errorf("%s[%d] matches no element of the schema: %v", path, i, err)
+			}
+		}
+	default:
+		if reflect.TypeOf(document) != reflect.TypeOf(sample) {
+			return fmt.Errorf("%s is a %T instead of a %T", path, document, sample)
+		}
+	}
+	return nil
+}
+
+func Test_RenderVTT(t *testing.T) {
+	universe, c := exportedCharacter(t)
+	smite := Spell{Name: "Smite", Description: "Lightning", XP: 200, Attributes: map[string]interface{}{"range": "10m", "focus": "WP"}}
+	universe.Spells = []Spell{smite}
+	c.Gauges = map[string]Gauge{"Fate": {Name: "Fate", Value: 3}}
+	c.Talents = map[string]Talent{"Catfall": {Name: "Catfall", Value: 1, Description: "Falls softly"}}
+	c.Spells = map[string]Spell{"Smite": smite}
+	c.Skills["Common Lore: Imperium"] = Skill{Name: "Common Lore", Speciality: "Imperium", Tier: 1}
+
+	cases := []struct {
+		system string
+		values map[string]interface{}
+	}{
+		{
+			system: "foundry-dh2",
+			values: map[string]interface{}{
+				"system.characteristics.weaponSkill.base":                30.,
+				"system.characteristics.weaponSkill.advance":             5.,
+				"system.skills.awareness.advance":                        10.,
+				"system.skills.commonLore.specialities.imperium.advance": 0.,
+				"system.fate.max":        3.,
+				"system.experience.used": 400.,
+			},
+		},
+		{
+			system: "roll20-dh2",
+		},
+	}
+
+	for _, c2 := range cases {
+		var out bytes.Buffer
+		err := RenderVTT(&out, c, universe, "testdata", c2.system)
+		if err != nil {
+			t.Logf("Unexpected error on %s: %s", c2.system, err)
+			t.Fail()
+			continue
+		}
+
+		var document map[string]interface{}
+		err = json.Unmarshal(out.Bytes(), &document)
+		if err != nil {
+			t.Logf("Invalid JSON on %s: %s", c2.system, err)
+			t.Fail()
+			continue
+		}
+
+		raw, err := ioutil.ReadFile(filepath.Join("testdata", MappingDir, c2.system+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var schema map[string]interface{}
+		err = json.Unmarshal(raw, &schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = conform(document, schema, c2.system)
+		if err != nil {
+			t.Logf("Unexpected actor on %s: %s", c2.system, err)
+			t.Logf("%s", out.String())
+			t.Fail()
+		}
+
+		for path, value := range c2.values {
+			if getPath(document, path) != value {
+				t.Logf("Unexpected %s on %s: expected %v, having %v", path, c2.system, value, getPath(document, path))
+				t.Fail()
+			}
+		}
+
+		if !strings.Contains(out.String(), `"name": "Tom & Jerry"`) {
+			t.Logf("Missing name on %s", c2.system)
+			t.Fail()
+		}
+	}
+}
+
+func Test_setPath(t *testing.T) {
+	cases := []struct {
+		document map[string]interface{}
+		path     string
+		value    interface{}
+		out      map[string]interface{}
+		err      bool
+	}{
+		{
+			document: map[string]interface{}{},
+			path:     "system.fate.max",
+			value:    3,
+			out:      map[string]interface{}{"system": map[string]interface{}{"fate": map[string]interface{}{"max": 3}}},
+		},
+		{
+			document: map[string]interface{}{"attribs": []interface{}{map[string]interface{}{"name": "WS", "current": 30}}},
+			path:     "attribs[WS].current",
+			value:    35,
+			out:      map[string]interface{}{"attribs": []interface{}{map[string]interface{}{"name": "WS", "current": 35}}},
+		},
+		{
+			document: map[string]interface{}{},
+			path:     "attribs[WS].current",
+			value:    35,
+			out:      map[string]interface{}{"attribs": []interface{}{map[string]interface{}{"name": "WS", "current": 35}}},
+		},
+		{
+			document: map[string]interface{}{"system": 3},
+			path:     "system.fate",
+			value:    3,
+			err:      true,
+		},
+	}
+
+	for i, c := range cases {
+		err := setPath(c.document, c.path, c.value)
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(c.document, c.out) {
+			t.Logf("Unexpected output on case %d:", i+1)
+			t.Logf("	Expected %v", c.out)
+			t.Logf("	Having %v", c.document)
+			t.Fail()
+		}
+	}
+}
+
+func Test_getPath(t *testing.T) {
+	document := map[string]interface{}{
+		"system":  map[string]interface{}{"fate": 3},
+		"attribs": []interface{}{map[string]interface{}{"name": "WS", "current": 30}},
+	}
+
+	cases := []struct {
+		path string
+		out  interface{}
+	}{
+		{path: "system.fate", out: 3},
+		{path: "attribs[WS].current", out: 30},
+		{path: "attribs[BS].current", out: nil},
+		{path: "system.fate.max", out: nil},
+	}
+
+	for i, c := range cases {
+		out := getPath(document, c.path)
+		if !reflect.DeepEqual(out, c.out) {
+			t.Logf("Unexpected output on case %d: expected %v, having %v", i+1, c.out, out)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Mapping_Actor(t *testing.T) {
+	// The items are added to the list selected by the path, after those of
+	// the actor document.
+	m := Mapping{
+		Document: map[string]interface{}{
+			"lists": []interface{}{map[string]interface{}{"name": "items", "content": []interface{}{"knife"}}},
+		},
+		Items: "lists[items].content",
+		Rules: &ItemMapping{Properties: Properties{"name": "name"}},
+	}
+	v := View{Rules: []Rule{{Name: "fearless"}}}
+
+	actor, err := m.Actor(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{"knife", map[string]interface{}{"name": "Fearless"}}
+	if !reflect.DeepEqual(getPath(actor, m.Items), expected) {
+		t.Logf("Unexpected items: expected %v, having %v", expected, getPath(actor, m.Items))
+		t.Fail()
+	}
+}
+
+func Test_Mapping_Actor_Order(t *testing.T) {
+	// The properties of a skill are appended to the list in a fixed order.
+	m := Mapping{
+		Skills: map[string]Properties{
+			"Awareness": {
+				"effective": "attribs[effective].current",
+				"training":  "attribs[training].current",
+				"bonus":     "attribs[bonus].current",
+				"advances":  "attribs[advances].current",
+			},
+		},
+	}
+	v := View{Skills: []SkillView{{Skill: Skill{Name: "Awareness", Tier: 1}}}}
+
+	for i := 0; i < 20; i++ {
+		actor, err := m.Actor(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []interface{}{}
+		for _, attrib := range actor["attribs"].([]interface{}) {
+			names = append(names, attrib.(map[string]interface{})["name"])
+		}
+		expected := []interface{}{"advances", "bonus", "training", "effective"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Logf("Unexpected order: expected %v, having %v", expected, names)
+			t.FailNow()
+		}
+	}
+}
+
+func Test_Mapping_Check(t *testing.T) {
+	universe := Universe{
+		Spells: []Spell{{Name: "Smite", Attributes: map[string]interface{}{"range": "10m"}}},
+	}
+
+	cases := []struct {
+		mapping Mapping
+		err     bool
+	}{
+		{
+			mapping: Mapping{Characteristics: map[string]Properties{"WS": {"value": "ws"}}},
+		},
+		{
+			mapping: Mapping{Characteristics: map[string]Properties{"WS": {"tier": "ws"}}},
+			err:     true,
+		},
+		{
+			mapping: Mapping{Characteristics: map[string]Properties{"WS": {"start": "ws.base", "increase": "ws.advance"}}},
+		},
+		{
+			mapping: Mapping{Talents: &ItemMapping{Properties: Properties{"name": "name"}}},
+			err:     true,
+		},
+		{
+			mapping: Mapping{Items: "items", Spells: &ItemMapping{Properties: Properties{"name": "name", "range": "range"}}},
+		},
+		{
+			mapping: Mapping{Items: "items", Spells: &ItemMapping{Properties: Properties{"name": "name", "focus": "focus"}}},
+			err:     true,
+		},
+	}
+
+	for i, c := range cases {
+		err := c.mapping.Check(universe)
+		if (err != nil) != c.err {
+			t.Logf("Unexpected error on case %d: %v", i+1, err)
+			t.Fail()
+		}
+	}
+}